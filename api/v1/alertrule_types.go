@@ -17,13 +17,17 @@ limitations under the License.
 package v1
 
 import (
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
 
 // AlertRuleSpec defines the desired state of AlertRule
+// +kubebuilder:validation:XValidation:rule="has(self.expr) || has(self.rules)",message="either expr or rules must be set"
 type AlertRuleSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
@@ -31,12 +35,12 @@ type AlertRuleSpec struct {
 	// More info: https://book.kubebuilder.io/reference/markers/crd-validation.html
 
 	// Alert name for the rule
-	// +required
-	Alert string `json:"alert"`
+	// +optional
+	Alert string `json:"alert,omitempty"`
 
 	// Expression for the alert rule (PromQL)
-	// +required
-	Expr string `json:"expr"`
+	// +optional
+	Expr string `json:"expr,omitempty"`
 
 	// Severity level (critical, warning, info)
 	// +kubebuilder:validation:Enum=critical;warning;info
@@ -55,20 +59,302 @@ type AlertRuleSpec struct {
 	// +optional
 	Annotations map[string]string `json:"annotations,omitempty"`
 
-	// Reference to the Deployment that triggered this alert rule
+	// Additional alerting or recording rules emitted in the same group as the rule above.
+	// Severity and Labels of the spec are applied to every alerting rule as defaults.
+	// +kubebuilder:validation:MinItems=1
+	// +optional
+	Rules []Rule `json:"rules,omitempty"`
+
+	// Group is the name of the Prometheus rule group the rules are emitted into.
+	// When the operator aggregates AlertRules, AlertRules of a namespace sharing
+	// the same group are merged into one group. Defaults to "<name>-group".
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// GroupSettings configures the evaluation of the Prometheus rule group.
+	// When AlertRules are aggregated into a shared group, the settings of the
+	// first AlertRule by name setting a field apply to the whole group.
+	// +optional
+	GroupSettings *RuleGroupSettings `json:"groupSettings,omitempty"`
+
+	// Backend the rules are published to. Defaults to the backend configured on the operator.
+	// +kubebuilder:validation:Enum=PrometheusRule;Ruler;ConfigMap
+	// +optional
+	Backend string `json:"backend,omitempty"`
+
+	// Reference to the workload that triggered this alert rule
+	// +optional
+	WorkloadRef *WorkloadReference `json:"workloadRef,omitempty"`
+
+	// WorkloadSelector scopes the rules to the workloads of the namespace matching a label selector.
+	// The rules follow the matched workloads as they come and go.
+	// +optional
+	WorkloadSelector *WorkloadSelector `json:"workloadSelector,omitempty"`
+
+	// Tests are unit tests of the rules, evaluated against synthetic series before the rules
+	// are published. Rules whose tests fail are not published.
+	// +optional
+	Tests []RuleTest `json:"tests,omitempty"`
+
+	// Routing declares where Alertmanager sends the alerts of the AlertRule. The operator generates
+	// a prometheus-operator AlertmanagerConfig in the namespace of the AlertRule from it.
+	// +optional
+	Routing *Routing `json:"routing,omitempty"`
+
+	// Maintenance windows during which the alerts of the AlertRule are silenced in Alertmanager
 	// +optional
-	DeploymentRef *DeploymentReference `json:"deploymentRef,omitempty"`
+	Maintenance []MaintenanceWindow `json:"maintenance,omitempty"`
 }
 
-// DeploymentReference references a Deployment
-type DeploymentReference struct {
-	// Namespace of the Deployment
+// MaintenanceWindow is a recurring or one-off period during which the alerts of an AlertRule are silenced.
+// Either Schedule and Duration or End must be set.
+type MaintenanceWindow struct {
+	// Schedule of recurring windows in cron format, e.g. "0 2 * * 6" for every Saturday at 02:00
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+
+	// Duration of each recurring window, e.g. 2h
+	// +optional
+	Duration string `json:"duration,omitempty"`
+
+	// TimeZone of the schedule, e.g. Europe/Berlin. Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// Start of a one-off window. A one-off window without a start begins immediately.
+	// +optional
+	Start *metav1.Time `json:"start,omitempty"`
+
+	// End of a one-off window
+	// +optional
+	End *metav1.Time `json:"end,omitempty"`
+
+	// Comment recorded on the silences of the window
+	// +optional
+	Comment string `json:"comment,omitempty"`
+}
+
+// Routing is the Alertmanager route and receiver of the alerts of an AlertRule
+type Routing struct {
+	// Receiver the alerts are sent to
+	// +kubebuilder:validation:MinLength=1
 	// +required
-	Namespace string `json:"namespace"`
+	Receiver string `json:"receiver"`
+
+	// ReceiverConfig configures the integrations of the receiver in the format of an AlertmanagerConfig
+	// receiver, e.g. slackConfigs or webhookConfigs. A receiver without integrations discards the alerts.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Type=object
+	// +optional
+	ReceiverConfig *runtime.RawExtension `json:"receiverConfig,omitempty"`
+
+	// GroupBy lists the labels by which alerts are grouped into one notification
+	// +optional
+	GroupBy []string `json:"groupBy,omitempty"`
+
+	// RepeatInterval is how long to wait before sending a notification again for a firing alert, e.g. 4h
+	// +optional
+	RepeatInterval string `json:"repeatInterval,omitempty"`
+
+	// Matchers further restrict the alerts of the AlertRule that are sent to the receiver
+	// +optional
+	Matchers []RouteMatcher `json:"matchers,omitempty"`
+}
 
-	// Name of the Deployment
+// RouteMatcher matches a label of an alert
+type RouteMatcher struct {
+	// Name of the label
+	// +kubebuilder:validation:MinLength=1
 	// +required
 	Name string `json:"name"`
+
+	// Value the label is matched against
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// MatchType is the match operator. Defaults to =.
+	// +kubebuilder:validation:Enum="=";"!=";"=~";"!~"
+	// +optional
+	MatchType string `json:"matchType,omitempty"`
+}
+
+// Rule describes a single alerting or recording rule of an AlertRule.
+// Exactly one of Alert and Record must be set.
+// +kubebuilder:validation:XValidation:rule="has(self.alert) != has(self.record)",message="exactly one of alert or record must be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.record) || (!has(self.for) && !has(self.annotations))",message="recording rules do not support for and annotations"
+type Rule struct {
+	// Alert name for an alerting rule
+	// +optional
+	Alert string `json:"alert,omitempty"`
+
+	// Metric name the result of the expression is recorded as, for a recording rule
+	// +optional
+	Record string `json:"record,omitempty"`
+
+	// Expression for the rule (PromQL)
+	// +required
+	Expr string `json:"expr"`
+
+	// Duration for which the condition must be true before alerting
+	// +optional
+	For string `json:"for,omitempty"`
+
+	// Labels to add to the alert or recorded series, merged over the labels of the spec
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations for the alert
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// WorkloadReference references a workload in the namespace of the AlertRule
+type WorkloadReference struct {
+	// API version of the workload, e.g. apps/v1
+	// +required
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the workload
+	// +kubebuilder:validation:Enum=Deployment;StatefulSet;DaemonSet;Job;CronJob
+	// +required
+	Kind string `json:"kind"`
+
+	// Name of the workload
+	// +required
+	Name string `json:"name"`
+}
+
+// WorkloadSelector selects workloads in the namespace of the AlertRule by their labels
+type WorkloadSelector struct {
+	// Kind of the selected workloads
+	// +kubebuilder:validation:Enum=Deployment;StatefulSet;DaemonSet;Job;CronJob
+	// +kubebuilder:default=Deployment
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// Selector matching the labels of the workloads. An empty selector matches every workload of the kind.
+	// +required
+	Selector metav1.LabelSelector `json:"selector"`
+
+	// Mode of scoping the rules to the matched workloads. Matcher adds a regular expression
+	// matcher on the workload label to every vector selector of the expressions, and
+	// Expand emits a copy of each rule per matched workload.
+	// +kubebuilder:validation:Enum=Matcher;Expand
+	// +kubebuilder:default=Matcher
+	// +optional
+	Mode string `json:"mode,omitempty"`
+
+	// Label carrying the workload name in the metrics. Defaults to the kube-state-metrics
+	// label of the kind: deployment, statefulset, daemonset, job_name or cronjob.
+	// +optional
+	Label string `json:"label,omitempty"`
+}
+
+// Modes of scoping the rules of an AlertRule to the workloads of its WorkloadSelector
+const (
+	// WorkloadSelectorModeMatcher adds a regular expression matcher selecting every matched workload
+	WorkloadSelectorModeMatcher = "Matcher"
+
+	// WorkloadSelectorModeExpand emits a copy of each rule per matched workload
+	WorkloadSelectorModeExpand = "Expand"
+)
+
+// Backends AlertRules can be published to
+const (
+	// BackendPrometheusRule publishes rules as prometheus-operator PrometheusRule objects
+	BackendPrometheusRule = "PrometheusRule"
+
+	// BackendRuler publishes rule groups to a Mimir, Cortex or Loki ruler HTTP API
+	BackendRuler = "Ruler"
+
+	// BackendConfigMap publishes Prometheus rule files to a ConfigMap mounted by Prometheus
+	BackendConfigMap = "ConfigMap"
+)
+
+// RuleGroupSettings defines the evaluation settings of a Prometheus rule group
+type RuleGroupSettings struct {
+	// Interval between evaluations of the group (e.g. 30s, 1m).
+	// Defaults to the global evaluation interval of Prometheus.
+	// +optional
+	Interval string `json:"interval,omitempty"`
+
+	// Limit of alerts an alerting rule and series a recording rule of the group can produce.
+	// 0 means no limit.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Limit *int32 `json:"limit,omitempty"`
+
+	// QueryOffset delays the evaluation timestamp of the group's queries (e.g. 1m)
+	// +optional
+	QueryOffset string `json:"queryOffset,omitempty"`
+
+	// PartialResponseStrategy of the group when evaluated by the Thanos Ruler
+	// +kubebuilder:validation:Enum=warn;abort
+	// +optional
+	PartialResponseStrategy string `json:"partialResponseStrategy,omitempty"`
+}
+
+// MaxTestEvalTime is the latest eval time of a rule test. The tests run while the AlertRule is
+// reconciled and the number of rule evaluations grows with the eval time, so it is kept short.
+const MaxTestEvalTime = 6 * time.Hour
+
+// RuleTest is a unit test of the rules of an AlertRule, modelled after the rule tests of promtool
+type RuleTest struct {
+	// Name of the test, reported when it fails
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Interval between the samples of the input series (e.g. 1m). Defaults to 1m.
+	// +optional
+	Interval string `json:"interval,omitempty"`
+
+	// InputSeries are the synthetic series the rules are evaluated against
+	// +kubebuilder:validation:MinItems=1
+	// +required
+	InputSeries []TestSeries `json:"inputSeries"`
+
+	// AlertRuleTests are the alerts expected to be firing at given times
+	// +kubebuilder:validation:MinItems=1
+	// +required
+	AlertRuleTests []AlertRuleTest `json:"alertRuleTests"`
+}
+
+// TestSeries is a synthetic series in promtool notation
+type TestSeries struct {
+	// Series is the metric name and labels of the series, e.g. up{job="api"}
+	// +required
+	Series string `json:"series"`
+
+	// Values in expanding notation, e.g. "1 1 0x10" or "0+10x5 _ stale"
+	// +required
+	Values string `json:"values"`
+}
+
+// AlertRuleTest checks the alerts of an alerting rule firing at a point in time
+type AlertRuleTest struct {
+	// EvalTime is the time since the start of the test at which the alerts are checked (e.g. 10m), at most 6h
+	// +required
+	EvalTime string `json:"evalTime"`
+
+	// Alertname is the name of the alerting rule checked
+	// +required
+	Alertname string `json:"alertname"`
+
+	// ExpAlerts are the alerts expected to be firing. No alert must be firing when empty.
+	// +optional
+	ExpAlerts []ExpectedAlert `json:"expAlerts,omitempty"`
+}
+
+// ExpectedAlert is an alert expected to be firing
+type ExpectedAlert struct {
+	// ExpLabels are the labels of the alert, apart from alertname which is added implicitly
+	// +optional
+	ExpLabels map[string]string `json:"expLabels,omitempty"`
+
+	// ExpAnnotations are the expanded annotations of the alert
+	// +optional
+	ExpAnnotations map[string]string `json:"expAnnotations,omitempty"`
 }
 
 // AlertRuleStatus defines the observed state of AlertRule.
@@ -92,10 +378,126 @@ type AlertRuleStatus struct {
 	// +listMapKey=type
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// observedGeneration is the most recent generation of the AlertRule reconciled by the operator
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// generatedRef references the object generated from this AlertRule
+	// +optional
+	GeneratedRef *GeneratedReference `json:"generatedRef,omitempty"`
+
+	// ruleHash is a content hash of the rules emitted for this AlertRule
+	// +optional
+	RuleHash string `json:"ruleHash,omitempty"`
+
+	// ruleGroup is the name of the Prometheus rule group the rules of this AlertRule are emitted into
+	// +optional
+	RuleGroup string `json:"ruleGroup,omitempty"`
+
+	// publishedBackend is the backend the rules of this AlertRule were last published to. Together with
+	// ruleGroup it tells where to remove the rules from once spec.backend or spec.group changes.
+	// +optional
+	PublishedBackend string `json:"publishedBackend,omitempty"`
+
+	// lastSyncTime is the last time the generated object was successfully synced
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+
+	// matchedWorkloads lists the names of the workloads selected by spec.workloadSelector
+	// +optional
+	MatchedWorkloads []string `json:"matchedWorkloads,omitempty"`
+
+	// expressions reports the result of running the expression of every rule against Prometheus
+	// +optional
+	Expressions []ExpressionStatus `json:"expressions,omitempty"`
+
+	// lastQueryTime is the last time the expressions were run against Prometheus
+	// +optional
+	LastQueryTime *metav1.Time `json:"lastQueryTime,omitempty"`
+
+	// alertState is the most advanced state of the alerts of this AlertRule (inactive, pending or firing)
+	// +kubebuilder:validation:Enum=inactive;pending;firing
+	// +optional
+	AlertState string `json:"alertState,omitempty"`
+
+	// activeAlerts is the number of pending and firing alerts of this AlertRule
+	// +optional
+	ActiveAlerts int32 `json:"activeAlerts,omitempty"`
+
+	// lastFiredTime is the last time an alert of this AlertRule was observed firing
+	// +optional
+	LastFiredTime *metav1.Time `json:"lastFiredTime,omitempty"`
+
+	// silencedUntil is the end of the maintenance window the alerts of this AlertRule are currently silenced in
+	// +optional
+	SilencedUntil *metav1.Time `json:"silencedUntil,omitempty"`
+}
+
+// States of the alerts of an AlertRule
+const (
+	// AlertStateInactive means no alert of the AlertRule is active
+	AlertStateInactive = "inactive"
+
+	// AlertStatePending means an alert is active but has not been active for the for duration yet
+	AlertStatePending = "pending"
+
+	// AlertStateFiring means an alert of the AlertRule is firing
+	AlertStateFiring = "firing"
+)
+
+// ExpressionStatus is the result of running the expression of a rule against Prometheus
+type ExpressionStatus struct {
+	// name of the alerting or recording rule
+	Name string `json:"name"`
+
+	// series is the number of series the expression currently returns
+	Series int32 `json:"series"`
+
+	// zeroSeries is true when the expression currently matches no series. It is false when the query failed.
+	ZeroSeries bool `json:"zeroSeries"`
+
+	// missingMetrics lists the metrics referenced by the expression that Prometheus has no series of
+	// +optional
+	MissingMetrics []string `json:"missingMetrics,omitempty"`
+
+	// error returned by Prometheus for the expression
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// GeneratedReference identifies an object generated by the operator
+type GeneratedReference struct {
+	// APIVersion of the generated object. Empty for objects published outside the cluster,
+	// such as rule groups of a ruler.
+	// +optional
+	APIVersion string `json:"apiVersion,omitempty"`
+
+	// Kind of the generated object
+	Kind string `json:"kind"`
+
+	// Namespace of the generated object
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name of the generated object
+	Name string `json:"name"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Backend",type=string,JSONPath=`.status.generatedRef.kind`,priority=1
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+// +kubebuilder:printcolumn:name="Generated",type=string,JSONPath=`.status.generatedRef.name`
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.alertState`
+// +kubebuilder:printcolumn:name="Active",type=integer,JSONPath=`.status.activeAlerts`
+// +kubebuilder:printcolumn:name="Last Fired",type=date,JSONPath=`.status.lastFiredTime`,priority=1
+// +kubebuilder:printcolumn:name="Silenced Until",type=date,JSONPath=`.status.silencedUntil`,priority=1
+// +kubebuilder:printcolumn:name="Group",type=string,JSONPath=`.status.ruleGroup`,priority=1
+// +kubebuilder:printcolumn:name="Hash",type=string,JSONPath=`.status.ruleHash`,priority=1
+// +kubebuilder:printcolumn:name="Last Sync",type=date,JSONPath=`.status.lastSyncTime`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // AlertRule is the Schema for the alertrules API
 type AlertRule struct {
@@ -0,0 +1,95 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AlertRuleTemplateSpec defines the desired state of AlertRuleTemplate
+type AlertRuleTemplateSpec struct {
+	// Kinds of workloads this template applies to
+	// +kubebuilder:default={Deployment}
+	// +kubebuilder:validation:items:Enum=Deployment;StatefulSet;DaemonSet;Job;CronJob
+	// +optional
+	Kinds []string `json:"kinds,omitempty"`
+
+	// Selects the workloads this template applies to by their labels.
+	// An empty selector matches every workload.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// Selects the namespaces whose workloads this template applies to.
+	// An empty selector matches every namespace.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// Severity level applied to rendered alerting rules that do not set a severity label
+	// +kubebuilder:validation:Enum=critical;warning;info
+	// +optional
+	Severity string `json:"severity,omitempty"`
+
+	// Rules rendered for every selected workload. The alert, record, expr and for fields
+	// and the values of labels and annotations are Go templates evaluated against the
+	// workload object, e.g. {{ .Name }} or {{ .Namespace }}.
+	// +kubebuilder:validation:MinItems=1
+	// +required
+	Rules []Rule `json:"rules"`
+}
+
+// AlertRuleTemplateStatus defines the observed state of AlertRuleTemplate.
+type AlertRuleTemplateStatus struct {
+	// conditions represent the current state of the AlertRuleTemplate resource.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+
+// AlertRuleTemplate is the Schema for the alertruletemplates API
+type AlertRuleTemplate struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the desired state of AlertRuleTemplate
+	// +required
+	Spec AlertRuleTemplateSpec `json:"spec"`
+
+	// status defines the observed state of AlertRuleTemplate
+	// +optional
+	Status AlertRuleTemplateStatus `json:"status,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// AlertRuleTemplateList contains a list of AlertRuleTemplate
+type AlertRuleTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []AlertRuleTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AlertRuleTemplate{}, &AlertRuleTemplateList{})
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+// Annotations read from workloads and Namespaces to control the automatic
+// generation of AlertRules. An annotation on a workload takes precedence over
+// the same annotation on its Namespace.
+const (
+	// AlertingAnnotation enables or disables AlertRule generation.
+	// Accepted values are AlertingEnabled and AlertingDisabled.
+	AlertingAnnotation = "monitoring.example.com/alerting"
+
+	// SeverityAnnotation overrides the severity of generated alerting rules
+	SeverityAnnotation = "monitoring.example.com/severity"
+
+	// ForAnnotation overrides the "for" duration of generated alerting rules
+	ForAnnotation = "monitoring.example.com/for"
+
+	// RulePolicyAnnotation selects how the generated AlertRule is kept in sync with the workload.
+	// Accepted values are RulePolicyOverwrite, RulePolicyPreserve and RulePolicyAdopt.
+	RulePolicyAnnotation = "monitoring.example.com/rule-policy"
+
+	// MaintenanceUntilAnnotation adds a maintenance window ending at the given RFC 3339 time to the
+	// generated AlertRule, silencing its alerts until then, e.g. during a rollout
+	MaintenanceUntilAnnotation = "monitoring.example.com/maintenance-until"
+)
+
+const (
+	// AlertingEnabled is the AlertingAnnotation value that opts in to AlertRule generation
+	AlertingEnabled = "enabled"
+
+	// AlertingDisabled is the AlertingAnnotation value that opts out of AlertRule generation
+	AlertingDisabled = "disabled"
+)
+
+const (
+	// RulePolicyOverwrite re-renders generated AlertRules, reverting edits made by users
+	RulePolicyOverwrite = "overwrite"
+
+	// RulePolicyPreserve re-renders generated AlertRules unless a user edited their spec
+	RulePolicyPreserve = "preserve"
+
+	// RulePolicyAdopt behaves like RulePolicyOverwrite and additionally takes over an existing
+	// AlertRule with the generated name that was not created by the operator
+	RulePolicyAdopt = "adopt"
+)
+
+// Annotations read from Namespaces to control how the rules of their AlertRules are published
+const (
+	// TenantIsolationAnnotation overrides whether a namespace="<namespace>" matcher is injected into
+	// the expressions of the AlertRules of a Namespace, restricting them to the Namespace's own metrics.
+	// Accepted values are TenantIsolationEnforced and TenantIsolationDisabled.
+	TenantIsolationAnnotation = "monitoring.example.com/tenant-isolation"
+)
+
+const (
+	// TenantIsolationEnforced is the TenantIsolationAnnotation value that injects the namespace matcher
+	TenantIsolationEnforced = "enforced"
+
+	// TenantIsolationDisabled is the TenantIsolationAnnotation value that publishes expressions as written
+	TenantIsolationDisabled = "disabled"
+)
+
+// Metadata set by the operator on the AlertRules it generates for workloads
+const (
+	// GeneratedLabel marks an AlertRule generated for a workload, with the value "true"
+	GeneratedLabel = "monitoring.example.com/generated"
+
+	// GeneratedSpecHashAnnotation records the hash of the spec last rendered for a generated
+	// AlertRule, which tells an edit by a user apart from an outdated rendering
+	GeneratedSpecHashAnnotation = "monitoring.example.com/generated-spec-hash"
+)
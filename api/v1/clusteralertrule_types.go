@@ -0,0 +1,66 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`
+// +kubebuilder:printcolumn:name="Namespace",type=string,JSONPath=`.status.generatedRef.namespace`
+// +kubebuilder:printcolumn:name="Generated",type=string,JSONPath=`.status.generatedRef.name`
+// +kubebuilder:printcolumn:name="Group",type=string,JSONPath=`.status.ruleGroup`,priority=1
+// +kubebuilder:printcolumn:name="Hash",type=string,JSONPath=`.status.ruleHash`,priority=1
+// +kubebuilder:printcolumn:name="Last Sync",type=date,JSONPath=`.status.lastSyncTime`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ClusterAlertRule is the Schema for the clusteralertrules API. It declares cluster-wide
+// rules, which are published as a PrometheusRule in the operator namespace.
+type ClusterAlertRule struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the desired state of ClusterAlertRule. The workload reference, workload
+	// selector, backend, routing and maintenance windows of the spec do not apply to
+	// cluster-wide rules and are rejected by the validating webhook.
+	// +required
+	Spec AlertRuleSpec `json:"spec"`
+
+	// status defines the observed state of ClusterAlertRule
+	// +optional
+	Status AlertRuleStatus `json:"status,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterAlertRuleList contains a list of ClusterAlertRule
+type ClusterAlertRuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []ClusterAlertRule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterAlertRule{}, &ClusterAlertRuleList{})
+}
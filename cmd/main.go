@@ -19,12 +19,19 @@ package main
 import (
 	"crypto/tls"
 	"flag"
+	"fmt"
 	"os"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	// Embed the time zone database for the time zones of maintenance windows,
+	// since the distroless base image does not ship one.
+	_ "time/tzdata"
+
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -37,6 +44,7 @@ import (
 
 	monitoringv1 "github.com/Kim-Yukyung/k8s-alert-rule-operator/api/v1"
 	"github.com/Kim-Yukyung/k8s-alert-rule-operator/internal/controller"
+	webhookmonitoringv1 "github.com/Kim-Yukyung/k8s-alert-rule-operator/internal/webhook/v1"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -61,6 +69,18 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var workloadNamespaceSelector string
+	var generatedRulePolicy string
+	var aggregatePrometheusRules bool
+	var defaultBackend string
+	var rulerURL, rulerTenant string
+	var ruleConfigMapName, ruleConfigMapNamespace string
+	var enforceNamespaceMatcher bool
+	var clusterRuleNamespace string
+	var prometheusURL, alertsURL, alertsSource string
+	var prometheusQueryInterval time.Duration
+	var alertmanagerURL string
+	var silenceSyncInterval time.Duration
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -79,6 +99,47 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.StringVar(&workloadNamespaceSelector, "workload-namespace-selector", "",
+		"Label selector restricting the namespaces in which AlertRules are generated for workloads "+
+			"(e.g. 'alerting=enabled'). Leave empty to generate AlertRules in every namespace.")
+	flag.StringVar(&generatedRulePolicy, "generated-rule-policy", monitoringv1.RulePolicyPreserve,
+		"How AlertRules generated for workloads are kept in sync: overwrite reverts user edits, "+
+			"preserve keeps edited AlertRules, adopt also takes over existing AlertRules with the generated name.")
+	flag.BoolVar(&aggregatePrometheusRules, "aggregate-prometheus-rules", false,
+		"If set, all AlertRules of a namespace are merged into a single PrometheusRule "+
+			"with one group per AlertRule group instead of one PrometheusRule per AlertRule.")
+	flag.StringVar(&defaultBackend, "default-backend", monitoringv1.BackendPrometheusRule,
+		"The backend AlertRules not selecting one are published to: PrometheusRule, Ruler or ConfigMap.")
+	flag.StringVar(&rulerURL, "ruler-url", "",
+		"The URL of a Mimir, Cortex or Loki ruler (e.g. http://mimir-ruler:8080) used by the Ruler backend.")
+	flag.StringVar(&rulerTenant, "ruler-tenant", "",
+		"The tenant sent in the X-Scope-OrgID header to the ruler. Leave empty for single-tenant rulers.")
+	flag.StringVar(&ruleConfigMapName, "rule-configmap-name", "alert-rule-operator-rules",
+		"The name of the ConfigMap holding the Prometheus rule files of the ConfigMap backend.")
+	flag.StringVar(&ruleConfigMapNamespace, "rule-configmap-namespace", "",
+		"The namespace of the ConfigMap of the ConfigMap backend, usually the namespace Prometheus runs in.")
+	flag.BoolVar(&enforceNamespaceMatcher, "enforce-namespace-matcher", false,
+		"If set, a namespace matcher for the AlertRule's namespace is injected into every vector selector of its "+
+			"expressions. Namespaces override it with the monitoring.example.com/tenant-isolation annotation.")
+	flag.StringVar(&clusterRuleNamespace, "cluster-rule-namespace", os.Getenv("POD_NAMESPACE"),
+		"The namespace the PrometheusRules of ClusterAlertRules are created in. Defaults to the namespace "+
+			"of the operator from the POD_NAMESPACE environment variable.")
+	flag.StringVar(&prometheusURL, "prometheus-url", "",
+		"The URL of a Prometheus (e.g. http://prometheus-operated.monitoring:9090) the expressions of AlertRules "+
+			"are periodically run against. Leave empty to disable the expression check.")
+	flag.StringVar(&alertsURL, "alerts-url", "",
+		"The URL of the Prometheus or Alertmanager the alert states of AlertRules are read from. "+
+			"Defaults to --prometheus-url.")
+	flag.StringVar(&alertsSource, "alerts-source", controller.AlertsSourcePrometheus,
+		"The API served at --alerts-url: Prometheus reads pending and firing alerts from /api/v1/rules, "+
+			"Alertmanager reads the alerts that are neither silenced nor inhibited from /api/v2/alerts.")
+	flag.DurationVar(&prometheusQueryInterval, "prometheus-query-interval", 5*time.Minute,
+		"The interval between two polls of Prometheus for the expressions and alert states of every AlertRule.")
+	flag.StringVar(&alertmanagerURL, "alertmanager-url", "",
+		"The URL of the Alertmanager (e.g. http://alertmanager-operated.monitoring:9093) the maintenance windows "+
+			"of AlertRules are silenced in through its v2 API. Leave empty to disable maintenance windows.")
+	flag.DurationVar(&silenceSyncInterval, "silence-sync-interval", time.Minute,
+		"The interval between two syncs of the silences of the maintenance windows of every AlertRule.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -178,21 +239,114 @@ func main() {
 		os.Exit(1)
 	}
 
+	switch defaultBackend {
+	case monitoringv1.BackendPrometheusRule, monitoringv1.BackendRuler, monitoringv1.BackendConfigMap:
+	default:
+		setupLog.Error(fmt.Errorf("unknown backend %q", defaultBackend), "invalid --default-backend")
+		os.Exit(1)
+	}
+
+	switch alertsSource {
+	case controller.AlertsSourcePrometheus, controller.AlertsSourceAlertmanager:
+	default:
+		setupLog.Error(fmt.Errorf("unknown alerts source %q", alertsSource), "invalid --alerts-source")
+		os.Exit(1)
+	}
+
+	// ClusterAlertRule은 항상 허용되므로 게시할 namespace 없이 시작하지 않음
+	if clusterRuleNamespace == "" {
+		setupLog.Error(fmt.Errorf("no namespace for the PrometheusRules of ClusterAlertRules"),
+			"--cluster-rule-namespace or the POD_NAMESPACE environment variable must be set")
+		os.Exit(1)
+	}
+
 	if err := (&controller.AlertRuleReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:         mgr.GetClient(),
+		Scheme:         mgr.GetScheme(),
+		Recorder:       mgr.GetEventRecorderFor("alertrule-controller"),
+		Aggregate:      aggregatePrometheusRules,
+		DefaultBackend: defaultBackend,
+		Ruler: controller.RulerConfig{
+			URL:    rulerURL,
+			Tenant: rulerTenant,
+		},
+		ConfigMap: controller.ConfigMapConfig{
+			Name:      ruleConfigMapName,
+			Namespace: ruleConfigMapNamespace,
+		},
+		EnforceNamespaceMatcher: enforceNamespaceMatcher,
+		Prometheus: controller.PrometheusConfig{
+			URL:          prometheusURL,
+			AlertsURL:    alertsURL,
+			AlertsSource: alertsSource,
+			Interval:     prometheusQueryInterval,
+		},
+		Silences: controller.SilenceConfig{
+			URL:      alertmanagerURL,
+			Interval: silenceSyncInterval,
+		},
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "AlertRule")
 		os.Exit(1)
 	}
 
-	if err := (&controller.DeploymentReconciler{
+	var namespaceSelector labels.Selector
+	if workloadNamespaceSelector != "" {
+		namespaceSelector, err = labels.Parse(workloadNamespaceSelector)
+		if err != nil {
+			setupLog.Error(err, "unable to parse workload namespace selector")
+			os.Exit(1)
+		}
+	}
+
+	switch generatedRulePolicy {
+	case monitoringv1.RulePolicyOverwrite, monitoringv1.RulePolicyPreserve, monitoringv1.RulePolicyAdopt:
+	default:
+		setupLog.Error(fmt.Errorf("unknown rule policy %q", generatedRulePolicy), "invalid --generated-rule-policy")
+		os.Exit(1)
+	}
+
+	for _, kind := range controller.SupportedWorkloadKinds {
+		if err := (&controller.WorkloadReconciler{
+			Client:            mgr.GetClient(),
+			Scheme:            mgr.GetScheme(),
+			Kind:              kind,
+			NamespaceSelector: namespaceSelector,
+			RulePolicy:        generatedRulePolicy,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", kind)
+			os.Exit(1)
+		}
+	}
+
+	if err := (&controller.AlertRuleTemplateReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
 	}).SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "Deployment")
+		setupLog.Error(err, "unable to create controller", "controller", "AlertRuleTemplate")
+		os.Exit(1)
+	}
+
+	if err := (&controller.ClusterAlertRuleReconciler{
+		Client:    mgr.GetClient(),
+		Scheme:    mgr.GetScheme(),
+		Recorder:  mgr.GetEventRecorderFor("clusteralertrule-controller"),
+		Namespace: clusterRuleNamespace,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterAlertRule")
 		os.Exit(1)
 	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err := webhookmonitoringv1.SetupAlertRuleWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "AlertRule")
+			os.Exit(1)
+		}
+		if err := webhookmonitoringv1.SetupClusterAlertRuleWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ClusterAlertRule")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
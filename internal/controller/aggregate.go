@@ -0,0 +1,233 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	monitoringv1 "github.com/Kim-Yukyung/k8s-alert-rule-operator/api/v1"
+)
+
+// aggregatedPrometheusRuleName is the name of the PrometheusRule holding every AlertRule of a namespace
+const aggregatedPrometheusRuleName = "alert-rule-operator-rules"
+
+// reconcileAggregatedPrometheusRule merges all AlertRules of a namespace published as PrometheusRules
+// into a single PrometheusRule. The PrometheusRule is deleted once the namespace has no such AlertRules left.
+// Like the PrometheusRule of a single AlertRule, manual changes are reverted and the returned string describes them.
+// The content hashes of the rules of the merged AlertRules are returned as well.
+func (r *AlertRuleReconciler) reconcileAggregatedPrometheusRule(ctx context.Context, namespace string) (string, ruleHashes, error) {
+	logger := logf.FromContext(ctx)
+
+	alertRuleList := &monitoringv1.AlertRuleList{}
+	if err := r.List(ctx, alertRuleList, client.InNamespace(namespace)); err != nil {
+		return "", nil, fmt.Errorf("unable to list AlertRules: %w", err)
+	}
+
+	// 삭제 중이거나 다른 backend로 게시되는 AlertRule은 제외
+	alertRules := make([]monitoringv1.AlertRule, 0, len(alertRuleList.Items))
+	for _, alertRule := range alertRuleList.Items {
+		if !alertRule.DeletionTimestamp.IsZero() || r.backendFor(&alertRule).Name() != monitoringv1.BackendPrometheusRule {
+			continue
+		}
+		alertRules = append(alertRules, alertRule)
+	}
+
+	if len(alertRules) == 0 {
+		logger.Info("No PrometheusRule AlertRules left in namespace, deleting aggregated PrometheusRule", "namespace", namespace)
+		return "", nil, r.deletePrometheusRule(ctx, namespace, aggregatedPrometheusRuleName)
+	}
+
+	desired, hashes, err := r.buildAggregatedPrometheusRule(ctx, namespace, alertRules)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// 모든 AlertRule이 현재 generation으로 게시된 상태라면 PrometheusRule이 없어진 것은 수동 삭제
+	published := true
+	for i := range alertRules {
+		ready := meta.FindStatusCondition(alertRules[i].Status.Conditions, "PrometheusRuleReady")
+		if ready == nil || ready.Status != metav1.ConditionTrue || ready.ObservedGeneration != alertRules[i].Generation {
+			published = false
+			break
+		}
+	}
+
+	logger.V(1).Info("Syncing aggregated PrometheusRule", "namespace", namespace, "alertrules", len(alertRules))
+	drift, err := r.syncPrometheusRule(ctx, desired, published)
+
+	return drift, hashes, err
+}
+
+// deleteStandalonePrometheusRule removes the PrometheusRule created for an AlertRule before
+// aggregation was enabled, so its rules are not evaluated twice
+func (r *AlertRuleReconciler) deleteStandalonePrometheusRule(ctx context.Context, alertRule *monitoringv1.AlertRule) error {
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(prometheusRuleGVK())
+	if err := r.Get(ctx, client.ObjectKey{Namespace: alertRule.Namespace, Name: alertRule.Name}, existing); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	// AlertRule이 controller인 PrometheusRule만 삭제
+	if !metav1.IsControlledBy(existing, alertRule) {
+		return nil
+	}
+
+	logf.FromContext(ctx).Info("Deleting standalone PrometheusRule replaced by aggregation", "prometheusrule", existing.GetName())
+	return client.IgnoreNotFound(r.Delete(ctx, existing))
+}
+
+// buildAggregatedPrometheusRule builds the PrometheusRule of a namespace with one group per
+// rule group of the given AlertRules, owned by all of them. Its generationAnnotation holds a
+// hash of the generations of the AlertRules, so a change of the PrometheusRule while none of
+// them changed is recognized as a manual change.
+func (r *AlertRuleReconciler) buildAggregatedPrometheusRule(ctx context.Context, namespace string,
+	alertRules []monitoringv1.AlertRule) (*unstructured.Unstructured, ruleHashes, error) {
+	prometheusRule := &unstructured.Unstructured{}
+	prometheusRule.SetGroupVersionKind(prometheusRuleGVK())
+	prometheusRule.SetName(aggregatedPrometheusRuleName)
+	prometheusRule.SetNamespace(namespace)
+	prometheusRule.SetLabels(map[string]string{
+		"managed-by": "alert-rule-operator",
+		"release":    "monitoring",
+	})
+	prometheusRule.SetAnnotations(map[string]string{
+		generationAnnotation: aggregatedGeneration(alertRules),
+	})
+
+	// 모든 AlertRule이 소유하므로 마지막 AlertRule이 삭제될 때 GC됨
+	for i := range alertRules {
+		if err := controllerutil.SetOwnerReference(&alertRules[i], prometheusRule, r.Scheme); err != nil {
+			logf.Log.Error(err, "unable to set owner reference", "alertrule", alertRules[i].Name)
+		}
+	}
+
+	groups, hashes, err := r.buildRuleGroups(ctx, alertRules)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := unstructured.SetNestedSlice(prometheusRule.Object, groups, "spec", "groups"); err != nil {
+		logf.Log.Error(err, "unable to set PrometheusRule spec")
+	}
+
+	return prometheusRule, hashes, nil
+}
+
+// aggregatedGeneration returns a hash of the UIDs and generations of the AlertRules of an aggregated PrometheusRule
+func aggregatedGeneration(alertRules []monitoringv1.AlertRule) string {
+	generations := make([]string, 0, len(alertRules))
+	for _, alertRule := range alertRules {
+		generations = append(generations, fmt.Sprintf("%s/%d", alertRule.UID, alertRule.Generation))
+	}
+	sort.Strings(generations)
+	sum := sha256.Sum256([]byte(strings.Join(generations, ",")))
+
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// buildRuleGroups builds one Prometheus rule group per rule group of the given AlertRules.
+// Groups and their members are ordered by name so that the result is stable across reconciles.
+// AlertRules whose tests fail, whose templates cannot be rendered or whose rules cannot be
+// restricted to their namespace are left out, and their Ready condition explains why.
+// The content hash of the rules built for each AlertRule is returned as well, matching the
+// hash of the PrometheusRule the AlertRule would be published as on its own.
+func (r *AlertRuleReconciler) buildRuleGroups(ctx context.Context, alertRules []monitoringv1.AlertRule) ([]interface{}, ruleHashes, error) {
+	sort.Slice(alertRules, func(i, j int) bool { return alertRules[i].Name < alertRules[j].Name })
+
+	recordingRules := map[string][]interface{}{}
+	alertingRules := map[string][]interface{}{}
+	settings := map[string]map[string]interface{}{}
+	groupNames := []string{}
+	hashes := ruleHashes{}
+	for i := range alertRules {
+		alertRule := &alertRules[i]
+
+		// 렌더링이나 namespace 격리에 실패했거나 테스트를 통과하지 못한 AlertRule의 rule은 게시하지 않음
+		recording, alerting, err := r.ruleSets(ctx, alertRule)
+		if err == nil && r.testsFailing(ctx, alertRule) {
+			err = &unpublishableError{reason: "TestsFailed", err: fmt.Errorf("the tests of the AlertRule fail")}
+		}
+		var unpublishable *unpublishableError
+		if errors.As(err, &unpublishable) {
+			// AlertRule 자신의 reconcile에서도 같은 condition을 기록하므로 실패해도 계속 진행
+			if err := r.reportUnpublished(ctx, alertRule, unpublishable); err != nil {
+				logf.FromContext(ctx).Error(err, "unable to update AlertRule status", "alertrule", alertRule.Name)
+			}
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to build rules of AlertRule %s: %w", alertRule.Name, err)
+		}
+
+		group := ruleGroupName(alertRule)
+		own := map[string]interface{}{
+			"name":  group,
+			"rules": append(append([]interface{}{}, recording...), alerting...),
+		}
+		setGroupSettings(own, alertRule.Spec.GroupSettings)
+		hashes[alertRule.Name] = groupsHash([]interface{}{own})
+
+		if _, ok := settings[group]; !ok {
+			groupNames = append(groupNames, group)
+			settings[group] = map[string]interface{}{}
+		}
+		setGroupSettings(settings[group], alertRule.Spec.GroupSettings)
+		recordingRules[group] = append(recordingRules[group], recording...)
+		alertingRules[group] = append(alertingRules[group], alerting...)
+	}
+	sort.Strings(groupNames)
+
+	// 그룹마다 recording rule을 alerting rule보다 먼저 배치
+	groups := make([]interface{}, 0, len(groupNames))
+	for _, group := range groupNames {
+		settings[group]["name"] = group
+		settings[group]["rules"] = append(recordingRules[group], alertingRules[group]...)
+		groups = append(groups, settings[group])
+	}
+
+	return groups, hashes, nil
+}
+
+// alertRulesOwning maps an aggregated PrometheusRule to the AlertRules owning it
+func alertRulesOwning(_ context.Context, obj client.Object) []reconcile.Request {
+	requests := []reconcile.Request{}
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.Kind != "AlertRule" || ref.APIVersion != monitoringv1.GroupVersion.String() {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: ref.Name},
+		})
+	}
+
+	return requests
+}
@@ -0,0 +1,253 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/prometheus/common/model"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	monitoringv1 "github.com/Kim-Yukyung/k8s-alert-rule-operator/api/v1"
+)
+
+// alertRuleLabel is set by the operator on every alerting rule it builds, to the AlertRule the rule
+// belongs to, so that the alerts of the rule can be told apart from alerts of other AlertRules with the
+// same name without relying on the labels of the alerted series
+const alertRuleLabel = "alertrule"
+
+// liveAlert is an active alert read from the rules API of Prometheus or the alerts API of Alertmanager
+type liveAlert struct {
+	// group is the rule group of the alert, empty for the alerts of Alertmanager
+	group  string
+	labels map[string]string
+	state  string
+}
+
+// syncAlertStates reads the active alerts and records the alert state of every AlertRule in its status
+func (r *AlertRuleReconciler) syncAlertStates(ctx context.Context) {
+	logger := logf.FromContext(ctx).WithName("alert-state")
+
+	alerts, err := r.fetchAlerts(ctx)
+	if err != nil {
+		logger.Error(err, "unable to fetch alerts")
+		return
+	}
+
+	alertRuleList := &monitoringv1.AlertRuleList{}
+	if err := r.List(ctx, alertRuleList); err != nil {
+		logger.Error(err, "unable to list AlertRules")
+		return
+	}
+
+	for i := range alertRuleList.Items {
+		alertRule := &alertRuleList.Items[i]
+		if !alertRule.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		state, active, err := r.alertRuleState(ctx, alertRule, alerts)
+		if err != nil {
+			logger.Error(err, "unable to match alerts", "alertrule", alertRule.Name, "namespace", alertRule.Namespace)
+			continue
+		}
+		if err := r.updateAlertState(ctx, alertRule, state, active); err != nil {
+			logger.Error(err, "unable to update AlertRule status", "alertrule", alertRule.Name, "namespace", alertRule.Namespace)
+		}
+	}
+}
+
+// alertRuleState returns the most advanced state and the number of the active alerts of an AlertRule.
+// An alert belongs to an AlertRule when it was raised by one of its alerting rules, as identified by
+// the rule group, the alertname and the alertRuleLabel of the alert.
+func (r *AlertRuleReconciler) alertRuleState(ctx context.Context, alertRule *monitoringv1.AlertRule,
+	alerts []liveAlert) (string, int32, error) {
+	_, alerting := r.buildRuleSets(ctx, alertRule)
+	rules, err := testRules(alerting)
+	if err != nil {
+		return "", 0, err
+	}
+
+	state := monitoringv1.AlertStateInactive
+	var active int32
+	for _, alert := range alerts {
+		matched := false
+		for _, rule := range rules {
+			if alertMatchesRule(alert, rule, alertRule) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+
+		active++
+		if alert.state == monitoringv1.AlertStateFiring || state == monitoringv1.AlertStateInactive {
+			state = alert.state
+		}
+	}
+
+	return state, active, nil
+}
+
+// alertMatchesRule reports whether an alert was raised by a rule of an AlertRule
+func alertMatchesRule(alert liveAlert, rule testRule, alertRule *monitoringv1.AlertRule) bool {
+	if alert.group != "" && alert.group != ruleGroupName(alertRule) {
+		return false
+	}
+
+	return alert.labels[model.AlertNameLabel] == rule.alert && alert.labels[alertRuleLabel] == alertRuleIdentity(alertRule)
+}
+
+// alertRuleIdentity returns the value of the alertRuleLabel of the rules of an AlertRule
+func alertRuleIdentity(alertRule *monitoringv1.AlertRule) string {
+	if alertRule.Namespace == "" {
+		return alertRule.Name
+	}
+
+	return alertRule.Namespace + "/" + alertRule.Name
+}
+
+// updateAlertState records the alert state of an AlertRule in its status. Unchanged states are not written.
+func (r *AlertRuleReconciler) updateAlertState(ctx context.Context, alertRule *monitoringv1.AlertRule, state string, active int32) error {
+	original := alertRule.DeepCopy()
+
+	alertRule.Status.AlertState = state
+	alertRule.Status.ActiveAlerts = active
+	if state == monitoringv1.AlertStateFiring {
+		now := metav1.Now()
+		alertRule.Status.LastFiredTime = &now
+	}
+
+	if equality.Semantic.DeepEqual(original.Status, alertRule.Status) {
+		return nil
+	}
+
+	return r.Status().Patch(ctx, alertRule, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{}))
+}
+
+// fetchAlerts reads the active alerts from the rules API of Prometheus, which reports the alerts of every
+// rule with their pending or firing state, or from the v2 alerts API of Alertmanager, whose alerts have all
+// been sent by firing rules. Alerts silenced or inhibited in Alertmanager are left out.
+func (r *AlertRuleReconciler) fetchAlerts(ctx context.Context) ([]liveAlert, error) {
+	if r.Prometheus.AlertsSource == AlertsSourceAlertmanager {
+		return r.fetchAlertmanagerAlerts(ctx)
+	}
+
+	data, err := r.prometheusGet(ctx, r.Prometheus.alertsURL(), "/api/v1/rules", url.Values{"type": {"alert"}})
+	if err != nil {
+		return nil, err
+	}
+
+	rules := struct {
+		Groups []struct {
+			Name  string `json:"name"`
+			Rules []struct {
+				Name   string `json:"name"`
+				Alerts []struct {
+					Labels map[string]string `json:"labels"`
+					State  string            `json:"state"`
+				} `json:"alerts"`
+			} `json:"rules"`
+		} `json:"groups"`
+	}{}
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("unable to decode rules: %w", err)
+	}
+
+	var alerts []liveAlert
+	for _, group := range rules.Groups {
+		for _, rule := range group.Rules {
+			for _, alert := range rule.Alerts {
+				if alert.State != monitoringv1.AlertStatePending && alert.State != monitoringv1.AlertStateFiring {
+					continue
+				}
+				// alertname은 항상 rule 이름이지만 rule labels에 없을 수 있으므로 rule에서 가져옴
+				alertLabels := make(map[string]string, len(alert.Labels)+1)
+				for name, value := range alert.Labels {
+					alertLabels[name] = value
+				}
+				alertLabels[model.AlertNameLabel] = rule.Name
+				alerts = append(alerts, liveAlert{group: group.Name, labels: alertLabels, state: alert.State})
+			}
+		}
+	}
+
+	return alerts, nil
+}
+
+// fetchAlertmanagerAlerts reads the active alerts that are neither silenced nor inhibited from the v2 API
+// of Alertmanager
+func (r *AlertRuleReconciler) fetchAlertmanagerAlerts(ctx context.Context) ([]liveAlert, error) {
+	params := url.Values{"active": {"true"}, "silenced": {"false"}, "inhibited": {"false"}, "unprocessed": {"false"}}
+	target := strings.TrimSuffix(r.Prometheus.alertsURL(), "/") + "/api/v2/alerts?" + params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := r.Prometheus.Client
+	if httpClient == nil {
+		httpClient = defaultHTTPClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("alertmanager request /api/v2/alerts failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read alertmanager response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("alertmanager request /api/v2/alerts returned %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+
+	var received []struct {
+		Labels map[string]string `json:"labels"`
+		Status struct {
+			State string `json:"state"`
+		} `json:"status"`
+	}
+	if err := json.Unmarshal(data, &received); err != nil {
+		return nil, fmt.Errorf("unable to decode alerts: %w", err)
+	}
+
+	var alerts []liveAlert
+	for _, alert := range received {
+		// silence나 inhibition으로 suppressed 상태인 alert는 firing으로 세지 않음
+		if alert.Status.State != "active" {
+			continue
+		}
+		alerts = append(alerts, liveAlert{labels: alert.Labels, state: monitoringv1.AlertStateFiring})
+	}
+
+	return alerts, nil
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	monitoringv1 "github.com/Kim-Yukyung/k8s-alert-rule-operator/api/v1"
+)
+
+var _ = Describe("Alert state", func() {
+	var (
+		prometheus *fakePrometheus
+		server     *httptest.Server
+		fakeClient client.Client
+		reconciler *AlertRuleReconciler
+		checkout   *monitoringv1.AlertRule
+		payments   *monitoringv1.AlertRule
+	)
+
+	newAlert := func(state string, labels map[string]string) map[string]interface{} {
+		return map[string]interface{}{"labels": labels, "state": state, "activeAt": "2025-06-01T12:00:00Z"}
+	}
+	newGroup := func(name string, alerts ...interface{}) map[string]interface{} {
+		return map[string]interface{}{"name": name, "file": "rules.yaml", "rules": []interface{}{
+			map[string]interface{}{"type": "alerting", "name": "ServiceDown", "alerts": alerts},
+		}}
+	}
+
+	BeforeEach(func() {
+		prometheus = &fakePrometheus{}
+		server = httptest.NewServer(prometheus)
+
+		checkout = &monitoringv1.AlertRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "default"},
+			Spec: monitoringv1.AlertRuleSpec{
+				Alert:    "ServiceDown",
+				Expr:     `up{job="checkout"} == 0`,
+				Severity: "critical",
+				Labels:   map[string]string{"team": "checkout"},
+			},
+		}
+		payments = &monitoringv1.AlertRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "payments", Namespace: "default"},
+			Spec: monitoringv1.AlertRuleSpec{
+				Alert:    "ServiceDown",
+				Expr:     `up{job="payments"} == 0`,
+				Severity: "critical",
+				Labels:   map[string]string{"team": "payments"},
+			},
+		}
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(k8sClient.Scheme()).
+			WithObjects(checkout, payments).
+			WithStatusSubresource(checkout, payments).
+			Build()
+		reconciler = &AlertRuleReconciler{
+			Client:     fakeClient,
+			Scheme:     k8sClient.Scheme(),
+			Prometheus: PrometheusConfig{AlertsURL: server.URL},
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	get := func(alertRule *monitoringv1.AlertRule) *monitoringv1.AlertRule {
+		updated := &monitoringv1.AlertRule{}
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(alertRule), updated)).To(Succeed())
+		return updated
+	}
+
+	It("should record the state of the alerts of each AlertRule from Prometheus", func() {
+		prometheus.rules = map[string]interface{}{"groups": []interface{}{
+			newGroup("checkout-group",
+				// 집계하는 expression의 alert에는 namespace label이 없음
+				newAlert("firing", map[string]string{"alertrule": "default/checkout", "severity": "critical"}),
+				newAlert("pending", map[string]string{"alertrule": "default/checkout", "instance": "b"}),
+				newAlert("inactive", map[string]string{"alertrule": "default/checkout", "instance": "c"})),
+			newGroup("payments-group",
+				newAlert("pending", map[string]string{"alertrule": "default/payments", "namespace": "team-b"}),
+				// 다른 namespace의 같은 이름 AlertRule의 alert는 제외
+				newAlert("firing", map[string]string{"alertrule": "team-b/payments", "namespace": "default"})),
+			// operator가 만들지 않은 rule의 alert는 제외
+			newGroup("checkout-group", newAlert("firing", map[string]string{"namespace": "default", "team": "checkout"})),
+			newGroup("legacy", newAlert("firing", map[string]string{"alertrule": "default/checkout"})),
+		}}
+
+		reconciler.syncAlertStates(ctx)
+
+		updated := get(checkout)
+		Expect(updated.Status.AlertState).To(Equal(monitoringv1.AlertStateFiring))
+		Expect(updated.Status.ActiveAlerts).To(Equal(int32(2)))
+		Expect(updated.Status.LastFiredTime).NotTo(BeNil())
+
+		updated = get(payments)
+		Expect(updated.Status.AlertState).To(Equal(monitoringv1.AlertStatePending))
+		Expect(updated.Status.ActiveAlerts).To(Equal(int32(1)))
+		Expect(updated.Status.LastFiredTime).To(BeNil())
+
+		By("resolving the alerts")
+		prometheus.rules = map[string]interface{}{"groups": []interface{}{newGroup("checkout-group")}}
+		reconciler.syncAlertStates(ctx)
+
+		updated = get(checkout)
+		Expect(updated.Status.AlertState).To(Equal(monitoringv1.AlertStateInactive))
+		Expect(updated.Status.ActiveAlerts).To(BeZero())
+		Expect(updated.Status.LastFiredTime).NotTo(BeNil())
+	})
+
+	It("should count the alerts of Alertmanager that are neither silenced nor inhibited as firing", func() {
+		reconciler.Prometheus.AlertsSource = AlertsSourceAlertmanager
+		prometheus.alerts = []interface{}{
+			map[string]interface{}{
+				"labels": map[string]string{"alertname": "ServiceDown", "alertrule": "default/payments"},
+				"status": map[string]interface{}{"state": "active"},
+			},
+			map[string]interface{}{
+				"labels": map[string]string{"alertname": "ServiceDown", "alertrule": "default/checkout"},
+				"status": map[string]interface{}{"state": "suppressed"},
+			},
+		}
+
+		reconciler.syncAlertStates(ctx)
+
+		Expect(prometheus.alertsQuery.Get("silenced")).To(Equal("false"))
+		Expect(prometheus.alertsQuery.Get("inhibited")).To(Equal("false"))
+		Expect(get(checkout).Status.AlertState).To(Equal(monitoringv1.AlertStateInactive))
+		updated := get(payments)
+		Expect(updated.Status.AlertState).To(Equal(monitoringv1.AlertStateFiring))
+		Expect(updated.Status.ActiveAlerts).To(Equal(int32(1)))
+	})
+})
@@ -0,0 +1,235 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	monitoringv1 "github.com/Kim-Yukyung/k8s-alert-rule-operator/api/v1"
+)
+
+// alertmanagerConfigCRDName is the name of the CustomResourceDefinition serving AlertmanagerConfigs
+const alertmanagerConfigCRDName = "alertmanagerconfigs.monitoring.coreos.com"
+
+// reconcileRouting generates the AlertmanagerConfig routing the alerts of an AlertRule to its receiver
+// and records the outcome in the RoutingReady condition. The AlertmanagerConfig of an AlertRule
+// without routing is removed. Errors are returned only when the reconciliation should be retried.
+func (r *AlertRuleReconciler) reconcileRouting(ctx context.Context, alertRule *monitoringv1.AlertRule) error {
+	logger := logf.FromContext(ctx)
+
+	available, err := r.alertmanagerConfigCRDAvailable()
+	if err != nil {
+		return fmt.Errorf("unable to check AlertmanagerConfig CRD: %w", err)
+	}
+
+	routing := alertRule.Spec.Routing
+	if routing == nil {
+		meta.RemoveStatusCondition(&alertRule.Status.Conditions, "RoutingReady")
+		if !available {
+			return nil
+		}
+		return r.deleteAlertmanagerConfig(ctx, alertRule)
+	}
+
+	condition := metav1.Condition{
+		Type:               "RoutingReady",
+		Status:             metav1.ConditionTrue,
+		Reason:             "AlertmanagerConfigApplied",
+		Message:            fmt.Sprintf("Alerts are routed to receiver %s", routing.Receiver),
+		ObservedGeneration: alertRule.Generation,
+	}
+	defer func() { meta.SetStatusCondition(&alertRule.Status.Conditions, condition) }()
+
+	if !available {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "CRDNotInstalled"
+		condition.Message = fmt.Sprintf("CRD %s is not installed", alertmanagerConfigCRDName)
+		return nil
+	}
+
+	desired, err := r.buildAlertmanagerConfig(ctx, alertRule)
+	if err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "InvalidRouting"
+		condition.Message = err.Error()
+		return nil
+	}
+	if desired == nil {
+		// alerting rule이 없으면 namespace의 모든 alert를 가져가지 않도록 route를 만들지 않음
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "NoAlerts"
+		condition.Message = "The AlertRule has no alerting rules to route"
+		return r.deleteAlertmanagerConfig(ctx, alertRule)
+	}
+
+	logger.Info("Applying AlertmanagerConfig", "name", desired.GetName(), "namespace", desired.GetNamespace())
+	if err := r.Apply(ctx, client.ApplyConfigurationFromUnstructured(desired), client.FieldOwner(fieldManager)); err != nil {
+		if !isApplyConflict(err) {
+			return fmt.Errorf("unable to apply AlertmanagerConfig: %w", err)
+		}
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "ApplyConflict"
+		condition.Message = fmt.Sprintf("AlertmanagerConfig fields are managed by another field manager: %v", err)
+	}
+
+	return nil
+}
+
+// buildAlertmanagerConfig builds the AlertmanagerConfig of an AlertRule. Its route matches the alert
+// names of the AlertRule in addition to the matchers of the routing; prometheus-operator further
+// restricts it to the namespace of the AlertmanagerConfig. Nil is returned without alerting rules.
+func (r *AlertRuleReconciler) buildAlertmanagerConfig(ctx context.Context, alertRule *monitoringv1.AlertRule) (*unstructured.Unstructured, error) {
+	routing := alertRule.Spec.Routing
+
+	_, alerting := r.buildRuleSets(ctx, alertRule)
+	rules, err := testRules(alerting)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	var alertNames []string
+	for _, rule := range rules {
+		if !seen[rule.alert] {
+			seen[rule.alert] = true
+			alertNames = append(alertNames, rule.alert)
+		}
+	}
+	if len(alertNames) == 0 {
+		return nil, nil
+	}
+	sort.Strings(alertNames)
+
+	alertnameMatcher := map[string]interface{}{"name": "alertname", "value": alertNames[0], "matchType": "="}
+	if len(alertNames) > 1 {
+		quoted := make([]string, 0, len(alertNames))
+		for _, name := range alertNames {
+			quoted = append(quoted, regexp.QuoteMeta(name))
+		}
+		alertnameMatcher["value"] = strings.Join(quoted, "|")
+		alertnameMatcher["matchType"] = "=~"
+	}
+	matchers := []interface{}{alertnameMatcher}
+	for _, matcher := range routing.Matchers {
+		matchType := matcher.MatchType
+		if matchType == "" {
+			matchType = "="
+		}
+		matchers = append(matchers, map[string]interface{}{
+			"name":      matcher.Name,
+			"value":     matcher.Value,
+			"matchType": matchType,
+		})
+	}
+
+	route := map[string]interface{}{
+		"receiver": routing.Receiver,
+		"matchers": matchers,
+	}
+	if len(routing.GroupBy) > 0 {
+		groupBy := make([]interface{}, 0, len(routing.GroupBy))
+		for _, label := range routing.GroupBy {
+			groupBy = append(groupBy, label)
+		}
+		route["groupBy"] = groupBy
+	}
+	if routing.RepeatInterval != "" {
+		route["repeatInterval"] = routing.RepeatInterval
+	}
+
+	// receiverConfig의 integration 설정에 receiver 이름을 붙임
+	receiver := map[string]interface{}{}
+	if routing.ReceiverConfig != nil && len(routing.ReceiverConfig.Raw) > 0 {
+		if err := json.Unmarshal(routing.ReceiverConfig.Raw, &receiver); err != nil {
+			return nil, fmt.Errorf("invalid receiverConfig: %w", err)
+		}
+	}
+	receiver["name"] = routing.Receiver
+
+	alertmanagerConfig := &unstructured.Unstructured{}
+	alertmanagerConfig.SetGroupVersionKind(alertmanagerConfigGVK())
+	alertmanagerConfig.SetName(alertRule.Name)
+	alertmanagerConfig.SetNamespace(alertRule.Namespace)
+	alertmanagerConfig.SetLabels(map[string]string{"managed-by": "alert-rule-operator"})
+	if err := ctrl.SetControllerReference(alertRule, alertmanagerConfig, r.Scheme); err != nil {
+		return nil, fmt.Errorf("unable to set controller reference: %w", err)
+	}
+	alertmanagerConfig.Object["spec"] = map[string]interface{}{
+		"route":     route,
+		"receivers": []interface{}{receiver},
+	}
+
+	return alertmanagerConfig, nil
+}
+
+// deleteAlertmanagerConfig deletes the AlertmanagerConfig generated for an AlertRule.
+// AlertmanagerConfigs of the same name not controlled by the AlertRule are left alone.
+func (r *AlertRuleReconciler) deleteAlertmanagerConfig(ctx context.Context, alertRule *monitoringv1.AlertRule) error {
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(alertmanagerConfigGVK())
+	if err := r.Get(ctx, client.ObjectKey{Namespace: alertRule.Namespace, Name: alertRule.Name}, existing); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("unable to fetch AlertmanagerConfig: %w", err)
+	}
+	if !metav1.IsControlledBy(existing, alertRule) {
+		return nil
+	}
+
+	logf.FromContext(ctx).Info("Deleting AlertmanagerConfig of AlertRule without routing", "name", existing.GetName())
+	if err := r.Delete(ctx, existing); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("unable to delete AlertmanagerConfig: %w", err)
+	}
+
+	return nil
+}
+
+// alertmanagerConfigCRDAvailable reports whether the AlertmanagerConfig CRD is installed
+func (r *AlertRuleReconciler) alertmanagerConfigCRDAvailable() (bool, error) {
+	gvk := alertmanagerConfigGVK()
+	if _, err := r.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+		if meta.IsNoMatchError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// alertmanagerConfigGVK returns the GroupVersionKind for AlertmanagerConfig
+func alertmanagerConfigGVK() schema.GroupVersionKind {
+	return schema.GroupVersionKind{
+		Group:   "monitoring.coreos.com",
+		Version: "v1alpha1",
+		Kind:    "AlertmanagerConfig",
+	}
+}
@@ -18,31 +18,99 @@ package controller
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/csaupgrade"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	monitoringv1 "github.com/Kim-Yukyung/k8s-alert-rule-operator/api/v1"
 )
 
+// generationAnnotation records on a PrometheusRule the AlertRule generation it was rendered from, or a hash
+// of the generations of the merged AlertRules, which tells a manual edit of the PrometheusRule apart from a
+// pending AlertRule change
+const generationAnnotation = "monitoring.example.com/alertrule-generation"
+
+// prometheusRuleCRDName is the name of the CustomResourceDefinition serving PrometheusRules
+const prometheusRuleCRDName = "prometheusrules.monitoring.coreos.com"
+
+// cleanupFinalizer guarantees the published rules of an AlertRule are removed before it is deleted
+const cleanupFinalizer = "monitoring.example.com/cleanup"
+
+// fieldManager is the server-side apply field manager owning the generated PrometheusRule fields
+const fieldManager = "alert-rule-operator"
+
 // AlertRuleReconciler reconciles a AlertRule object
 type AlertRuleReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// Aggregate merges all AlertRules of a namespace into a single PrometheusRule
+	// instead of creating one PrometheusRule per AlertRule
+	Aggregate bool
+
+	// DefaultBackend is the backend of AlertRules not selecting one. Defaults to PrometheusRule.
+	DefaultBackend string
+
+	// Ruler configures the ruler HTTP API backend
+	Ruler RulerConfig
+
+	// ConfigMap configures the ConfigMap backend
+	ConfigMap ConfigMapConfig
+
+	// EnforceNamespaceMatcher injects a namespace="<namespace>" matcher into every vector selector
+	// of the emitted expressions. Namespaces override it with monitoringv1.TenantIsolationAnnotation.
+	EnforceNamespaceMatcher bool
+
+	// Prometheus configures the Prometheus the expressions of AlertRules are periodically run against
+	Prometheus PrometheusConfig
+
+	// Silences configures the Alertmanager the maintenance windows of AlertRules are silenced in
+	Silences SilenceConfig
+
+	// testResults caches the results of the rule tests of AlertRules
+	testResults ruleTestCache
+
+	// watches watches the generated PrometheusRules and AlertmanagerConfigs once their CRDs are installed
+	watches *generatedWatches
 }
 
 // +kubebuilder:rbac:groups=monitoring.example.com,resources=alertrules,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=monitoring.example.com,resources=alertrules/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=monitoring.example.com,resources=alertrules/finalizers,verbs=update
 // +kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=alertmanagerconfigs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -53,36 +121,158 @@ func (r *AlertRuleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	alertRule := &monitoringv1.AlertRule{}
 	if err := r.Get(ctx, req.NamespacedName, alertRule); err != nil {
 		if apierrors.IsNotFound(err) {
-			logger.Info("AlertRule not found, checking for PrometheusRule to delete", "name", req.Name, "namespace", req.Namespace)
-			return r.deletePrometheusRule(ctx, req.Namespace, req.Name)
+			// 게시된 rule은 finalizer에서 이미 정리됨
+			logger.Info("AlertRule not found, ignoring", "name", req.Name, "namespace", req.Namespace)
+			return ctrl.Result{}, nil
 		}
 		logger.Error(err, "unable to fetch AlertRule")
-		return ctrl.Result{}, client.IgnoreNotFound(err)
+		return ctrl.Result{}, err
 	}
 
-	// AlertRule이 삭제 중인 경우 스킵
+	// AlertRule이 삭제 중인 경우 게시된 rule을 정리한 뒤 finalizer 제거
 	if !alertRule.DeletionTimestamp.IsZero() {
-		logger.Info("AlertRule is being deleted, skipping reconciliation")
+		return r.finalize(ctx, alertRule)
+	}
+
+	// 삭제 전에 정리할 수 있도록 finalizer 추가
+	if !controllerutil.ContainsFinalizer(alertRule, cleanupFinalizer) {
+		controllerutil.AddFinalizer(alertRule, cleanupFinalizer)
+		if err := r.Update(ctx, alertRule); err != nil {
+			logger.Error(err, "unable to add finalizer")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// WorkloadSelector가 선택한 workload 갱신
+	if err := r.updateMatchedWorkloads(ctx, alertRule); err != nil {
+		logger.Error(err, "unable to resolve selected workloads")
+		return ctrl.Result{}, err
+	}
+
+	backend := r.backendFor(alertRule)
+
+	// 템플릿을 렌더링할 수 없으면 rule을 게시하지 않고 status에 기록
+	data, err := r.templateData(ctx, alertRule)
+	if err != nil {
+		logger.Error(err, "unable to resolve template data")
+		return ctrl.Result{}, err
+	}
+	rendered, err := renderAlertRule(alertRule, data)
+	if err != nil {
+		logger.Info("Unable to render AlertRule templates, skipping rule publication", "error", err.Error())
+		r.Recorder.Event(alertRule, corev1.EventTypeWarning, "RenderFailed", err.Error())
+		if err := r.updateRenderFailedStatus(ctx, alertRule, backend, err); err != nil {
+			logger.Error(err, "unable to update AlertRule status")
+			return ctrl.Result{}, err
+		}
 		return ctrl.Result{}, nil
 	}
 
-	// PrometheusRule 생성 또는 업데이트
-	logger.Info("Reconciling PrometheusRule for AlertRule", "alertrule", alertRule.Name, "namespace", alertRule.Namespace)
-	if err := r.reconcilePrometheusRule(ctx, alertRule); err != nil {
-		errStr := err.Error()
-		if apierrors.IsNotFound(err) || apierrors.IsInvalid(err) ||
-			apierrors.IsMethodNotSupported(err) ||
-			strings.Contains(errStr, "no matches for kind") ||
-			strings.Contains(errStr, "CRD may not be available") {
-			logger.Info("PrometheusRule CRD not available, skipping PrometheusRule creation", "error", err)
-		} else {
-			logger.Error(err, "unable to reconcile PrometheusRule")
+	// namespace matcher를 주입할 수 없는 rule이 있으면 격리되지 않은 rule을 게시하지 않고 status에 기록
+	failure, err := r.isolationFailure(ctx, rendered)
+	if err != nil {
+		logger.Error(err, "unable to resolve namespace isolation")
+		return ctrl.Result{}, err
+	}
+	if failure != "" {
+		logger.Info("Unable to enforce namespace isolation, skipping rule publication", "reason", failure)
+		r.Recorder.Event(alertRule, corev1.EventTypeWarning, "IsolationFailed", failure)
+		if err := r.updateIsolationFailedStatus(ctx, alertRule, backend, failure); err != nil {
+			logger.Error(err, "unable to update AlertRule status")
 			return ctrl.Result{}, err
 		}
+		return ctrl.Result{}, nil
+	}
+
+	// 테스트가 실패하면 rule을 게시하지 않고 status에 기록
+	if len(alertRule.Spec.Tests) > 0 {
+		failures, err := r.runRuleTests(ctx, alertRule)
+		if err != nil {
+			failures = []string{err.Error()}
+		}
+		if len(failures) > 0 {
+			message := strings.Join(failures, "; ")
+			logger.Info("AlertRule tests failed, skipping rule publication", "failures", message)
+			r.Recorder.Event(alertRule, corev1.EventTypeWarning, "TestsFailed", message)
+			if err := r.updateTestsFailedStatus(ctx, alertRule, backend, message); err != nil {
+				logger.Error(err, "unable to update AlertRule status")
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{}, nil
+		}
+	}
+	r.setTestsPassedCondition(alertRule)
+
+	// Backend 사용 가능 여부 확인
+	unavailable, err := backend.Available(ctx)
+	if err != nil {
+		logger.Error(err, "unable to check backend", "backend", backend.Name())
+		return ctrl.Result{}, err
+	}
+	if unavailable != "" {
+		// PrometheusRule CRD가 설치되면 CRD watch가 모든 AlertRule을 다시 큐에 넣음
+		logger.Info("Backend not available, skipping rule publication", "backend", backend.Name(), "reason", unavailable)
+		if err := r.updateBackendUnavailableStatus(ctx, alertRule, backend, unavailable); err != nil {
+			logger.Error(err, "unable to update AlertRule status")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// Rule 생성 또는 업데이트
+	logger.Info("Publishing rules for AlertRule", "alertrule", alertRule.Name, "namespace", alertRule.Namespace, "backend", backend.Name())
+	published, err := backend.Publish(ctx, alertRule)
+	var unpublishable *unpublishableError
+	if errors.As(err, &unpublishable) {
+		logger.Info("Unable to publish rules", "backend", backend.Name(), "reason", unpublishable.Error())
+		r.Recorder.Event(alertRule, corev1.EventTypeWarning, unpublishable.reason, unpublishable.Error())
+		if err := r.reportUnpublished(ctx, alertRule, unpublishable); err != nil {
+			logger.Error(err, "unable to update AlertRule status")
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+	var conflict error
+	if err != nil {
+		if !isApplyConflict(err) {
+			logger.Error(err, "unable to publish rules", "backend", backend.Name())
+			return ctrl.Result{}, err
+		}
+		// 다른 field manager와의 충돌은 status에 기록하고 재시도하지 않음
+		logger.Info("Server-side apply of PrometheusRule conflicts with another field manager", "error", err)
+		conflict = err
+	}
+
+	// Backend나 그룹을 바꾼 경우 이전에 게시한 rule 정리
+	if conflict == nil {
+		if err := r.unpublishPrevious(ctx, alertRule, backend); err != nil {
+			logger.Error(err, "unable to remove previously published rules")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Alertmanager routing을 AlertmanagerConfig로 생성
+	if err := r.reconcileRouting(ctx, alertRule); err != nil {
+		logger.Error(err, "unable to reconcile routing")
+		return ctrl.Result{}, err
+	}
+
+	// 유지보수 시간의 silence를 Alertmanager에 생성
+	if r.Silences.URL != "" {
+		if err := r.reconcileSilences(ctx, alertRule); err != nil {
+			// 실패한 경우 주기적인 동기화에서 다시 시도
+			logger.Error(err, "unable to sync maintenance silences")
+		}
+	}
+
+	// PrometheusRule의 수동 변경을 되돌린 경우 Event 기록
+	if published.drift != "" {
+		logger.Info("Reverted manual change of PrometheusRule", "reason", published.drift)
+		r.Recorder.Event(alertRule, corev1.EventTypeWarning, "DriftCorrected", published.drift)
 	}
 
 	// Status 업데이트
-	if err := r.updateStatus(ctx, alertRule); err != nil {
+	if err := r.updateStatus(ctx, alertRule, backend, published, conflict); err != nil {
 		logger.Error(err, "unable to update AlertRule status")
 		return ctrl.Result{}, err
 	}
@@ -90,48 +280,254 @@ func (r *AlertRuleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	return ctrl.Result{}, nil
 }
 
-// reconcilePrometheusRule creates or updates a PrometheusRule based on AlertRule
-func (r *AlertRuleReconciler) reconcilePrometheusRule(ctx context.Context, alertRule *monitoringv1.AlertRule) error {
+// unpublishPrevious removes the rules of an AlertRule from the backend and rule group recorded in its
+// status, once the AlertRule has been published to another backend or rule group
+func (r *AlertRuleReconciler) unpublishPrevious(ctx context.Context, alertRule *monitoringv1.AlertRule, backend RuleBackend) error {
+	previous := publishedBackend(alertRule)
+	group := publishedGroup(alertRule)
+	if previous == "" || (previous == backend.Name() && group == ruleGroupName(alertRule)) {
+		return nil
+	}
+
+	for _, candidate := range r.backends() {
+		if candidate.Name() != previous {
+			continue
+		}
+
+		logf.FromContext(ctx).Info("Removing rules from the previous backend", "backend", previous, "group", group)
+		err := candidate.Delete(ctx, alertRule, group)
+		if isApplyConflict(err) {
+			// 통합된 PrometheusRule의 충돌은 그 AlertRule들의 status에 기록되므로 이 AlertRule은 계속 진행
+			logf.FromContext(ctx).Info("Unable to remove rules from the aggregated PrometheusRule", "error", err.Error())
+			return nil
+		}
+		return err
+	}
+
+	// 더 이상 설정되지 않은 backend에서는 정리할 수 없음
+	return nil
+}
+
+// publishedBackend returns the backend the rules of an AlertRule were last published to. AlertRules
+// published before status.publishedBackend was recorded fall back to the kind of their generated object.
+func publishedBackend(alertRule *monitoringv1.AlertRule) string {
+	if alertRule.Status.PublishedBackend != "" {
+		return alertRule.Status.PublishedBackend
+	}
+	if alertRule.Status.GeneratedRef == nil {
+		return ""
+	}
+
+	switch alertRule.Status.GeneratedRef.Kind {
+	case "RuleGroup":
+		return monitoringv1.BackendRuler
+	case "ConfigMap":
+		return monitoringv1.BackendConfigMap
+	default:
+		return monitoringv1.BackendPrometheusRule
+	}
+}
+
+// publishedGroup returns the rule group the rules of an AlertRule were last published into
+func publishedGroup(alertRule *monitoringv1.AlertRule) string {
+	if alertRule.Status.RuleGroup != "" {
+		return alertRule.Status.RuleGroup
+	}
+
+	return ruleGroupName(alertRule)
+}
+
+// finalize removes the rules published for a deleted AlertRule from every configured backend,
+// since the backend may have changed since they were published, and then removes the finalizer.
+// While cleanup fails the finalizer is kept and a CleanupBlocked condition explains why.
+func (r *AlertRuleReconciler) finalize(ctx context.Context, alertRule *monitoringv1.AlertRule) (ctrl.Result, error) {
 	logger := logf.FromContext(ctx)
 
-	prometheusRuleName := alertRule.Name
+	if !controllerutil.ContainsFinalizer(alertRule, cleanupFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	logger.Info("Cleaning up rules of deleted AlertRule", "alertrule", alertRule.Name, "namespace", alertRule.Namespace)
+	group := publishedGroup(alertRule)
+	for _, backend := range r.backends() {
+		if err := backend.Delete(ctx, alertRule, group); err != nil {
+			logger.Error(err, "unable to clean up rules", "backend", backend.Name())
+
+			message := fmt.Sprintf("Unable to remove rules from the %s backend: %v", backend.Name(), err)
+			r.Recorder.Event(alertRule, corev1.EventTypeWarning, "CleanupBlocked", message)
+			meta.SetStatusCondition(&alertRule.Status.Conditions, metav1.Condition{
+				Type:               "CleanupBlocked",
+				Status:             metav1.ConditionTrue,
+				Reason:             "BackendCleanupFailed",
+				Message:            message,
+				ObservedGeneration: alertRule.Generation,
+			})
+			if statusErr := r.Status().Update(ctx, alertRule); statusErr != nil {
+				logger.Error(statusErr, "unable to update AlertRule status")
+			}
+			return ctrl.Result{}, err
+		}
+	}
+
+	// silence는 끝나는 시각에 자동으로 만료되므로 실패해도 삭제를 막지 않음
+	if r.Silences.URL != "" {
+		if err := r.expireSilences(ctx, alertRule); err != nil {
+			logger.Error(err, "unable to expire maintenance silences")
+		}
+	}
+
+	controllerutil.RemoveFinalizer(alertRule, cleanupFinalizer)
+	if err := r.Update(ctx, alertRule); err != nil {
+		logger.Error(err, "unable to remove finalizer")
+		return ctrl.Result{}, err
+	}
+	r.testResults.forget(alertRule.UID)
+
+	return ctrl.Result{}, nil
+}
+
+// reconcilePrometheusRule creates or updates the PrometheusRule built for an AlertRule using server-side apply,
+// so that only the generated fields are owned by the operator.
+// When a manual change of the PrometheusRule had to be reverted, the returned string describes it.
+func (r *AlertRuleReconciler) reconcilePrometheusRule(ctx context.Context, alertRule *monitoringv1.AlertRule,
+	prometheusRule *unstructured.Unstructured) (string, error) {
+	// 같은 generation으로 이미 생성했던 PrometheusRule이 없어졌다면 수동 삭제로 판단
+	ready := meta.FindStatusCondition(alertRule.Status.Conditions, "PrometheusRuleReady")
+	published := ready != nil && ready.Status == metav1.ConditionTrue && ready.ObservedGeneration == alertRule.Generation
+
+	return r.syncPrometheusRule(ctx, prometheusRule, published)
+}
+
+// syncPrometheusRule creates or updates a generated PrometheusRule. The generationAnnotation of the
+// desired PrometheusRule identifies what it was rendered from: an existing PrometheusRule carrying the
+// same annotation but different rules was modified manually, and the change is reverted by forcing the
+// ownership of its fields. published reports whether the PrometheusRule was already created from the
+// same source, in which case a missing PrometheusRule was deleted manually.
+// When a manual change of the PrometheusRule had to be reverted, the returned string describes it.
+func (r *AlertRuleReconciler) syncPrometheusRule(ctx context.Context, desired *unstructured.Unstructured, published bool) (string, error) {
+	logger := logf.FromContext(ctx)
 
 	// 기존 PrometheusRule 확인
 	existingRule := &unstructured.Unstructured{}
 	existingRule.SetGroupVersionKind(prometheusRuleGVK())
-	existingRule.SetName(prometheusRuleName)
-	existingRule.SetNamespace(alertRule.Namespace)
-
-	err := r.Get(ctx, client.ObjectKey{Namespace: alertRule.Namespace, Name: prometheusRuleName}, existingRule)
+	err := r.Get(ctx, client.ObjectKeyFromObject(desired), existingRule)
 	if err != nil && !apierrors.IsNotFound(err) {
-		return fmt.Errorf("unable to fetch PrometheusRule (CRD may not be available): %w", err)
+		return "", fmt.Errorf("unable to fetch PrometheusRule: %w", err)
 	}
 
-	prometheusRule := r.createPrometheusRule(alertRule)
-
 	if apierrors.IsNotFound(err) {
-		logger.Info("Creating PrometheusRule", "name", prometheusRuleName, "namespace", alertRule.Namespace)
-		if err := r.Create(ctx, prometheusRule); err != nil {
-			return fmt.Errorf("unable to create PrometheusRule: %w", err)
+		drift := ""
+		if published {
+			drift = fmt.Sprintf("PrometheusRule %s was deleted manually and has been recreated", desired.GetName())
 		}
-		logger.Info("Successfully created PrometheusRule", "name", prometheusRuleName)
-	} else {
-		logger.Info("Updating PrometheusRule", "name", prometheusRuleName, "namespace", alertRule.Namespace)
 
-		prometheusRule.SetUID(existingRule.GetUID())
-		prometheusRule.SetResourceVersion(existingRule.GetResourceVersion())
+		logger.Info("Creating PrometheusRule", "name", desired.GetName(), "namespace", desired.GetNamespace())
+		if err := r.applyPrometheusRule(ctx, desired, false); err != nil {
+			return "", fmt.Errorf("unable to create PrometheusRule: %w", err)
+		}
+		logger.Info("Successfully created PrometheusRule", "name", desired.GetName())
+		return drift, nil
+	}
+
+	sameSource := existingRule.GetAnnotations()[generationAnnotation] == desired.GetAnnotations()[generationAnnotation]
+	inSync := prometheusRuleInSync(existingRule, desired)
+	if inSync && sameSource {
+		return "", nil
+	}
+
+	// 생성 원본이 바뀌지 않았는데 내용이 다르면 수동 변경으로 판단
+	drift := ""
+	if !inSync && sameSource {
+		drift = fmt.Sprintf("PrometheusRule %s was modified manually and has been reverted", desired.GetName())
+	}
+
+	logger.Info("Updating PrometheusRule", "name", desired.GetName(), "namespace", desired.GetNamespace())
+
+	// 수동 변경을 되돌릴 때만 다른 field manager가 가져간 필드의 소유권을 강제로 회수
+	if err := r.applyPrometheusRule(ctx, desired, drift != ""); err != nil {
+		return "", fmt.Errorf("unable to update PrometheusRule: %w", err)
+	}
+	logger.Info("Successfully updated PrometheusRule", "name", desired.GetName())
+
+	return drift, nil
+}
+
+// applyPrometheusRule server-side applies the generated PrometheusRule with the operator's field manager.
+// Unless force is set, fields owned by another field manager with a different value result in a conflict error.
+func (r *AlertRuleReconciler) applyPrometheusRule(ctx context.Context, prometheusRule *unstructured.Unstructured, force bool) error {
+	// 이전 버전이 Update로 소유한 필드와 충돌하지 않도록 먼저 소유권을 이전
+	if err := r.upgradeManagedFields(ctx, prometheusRule); err != nil {
+		return fmt.Errorf("unable to migrate managed fields: %w", err)
+	}
+
+	opts := []client.ApplyOption{client.FieldOwner(fieldManager)}
+	if force {
+		opts = append(opts, client.ForceOwnership)
+	}
 
-		if err := r.Update(ctx, prometheusRule); err != nil {
-			return fmt.Errorf("unable to update PrometheusRule: %w", err)
+	return r.Apply(ctx, client.ApplyConfigurationFromUnstructured(prometheusRule), opts...)
+}
+
+// isApplyConflict reports whether a server-side apply failed because fields are owned by another
+// field manager. Other conflicts, such as a stale resourceVersion, are transient and retried.
+func isApplyConflict(err error) bool {
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) || status.Status().Reason != metav1.StatusReasonConflict || status.Status().Details == nil {
+		return false
+	}
+
+	for _, cause := range status.Status().Details.Causes {
+		if cause.Type == metav1.CauseTypeFieldManagerConflict {
+			return true
 		}
-		logger.Info("Successfully updated PrometheusRule", "name", prometheusRuleName)
 	}
 
-	return nil
+	return false
+}
+
+// upgradeManagedFields transfers the fields of an existing PrometheusRule that earlier versions of the
+// operator owned through Update requests to the server-side apply field manager
+func (r *AlertRuleReconciler) upgradeManagedFields(ctx context.Context, prometheusRule *unstructured.Unstructured) error {
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(prometheusRule.GroupVersionKind())
+	if err := r.Get(ctx, client.ObjectKeyFromObject(prometheusRule), existing); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	patch, err := csaupgrade.UpgradeManagedFieldsPatch(existing, legacyFieldManagers(), fieldManager)
+	if err != nil || patch == nil {
+		return err
+	}
+
+	logf.FromContext(ctx).Info("Migrating managed fields of PrometheusRule to server-side apply", "name", existing.GetName())
+	return r.Patch(ctx, existing, client.RawPatch(types.JSONPatchType, patch))
+}
+
+// legacyFieldManagers returns the field managers of the Update requests of earlier versions of the operator,
+// which the API server derives from the user agent of the binary (e.g. "manager")
+func legacyFieldManagers() sets.Set[string] {
+	return sets.New(strings.Split(rest.DefaultKubernetesUserAgent(), "/")[0])
+}
+
+// prometheusRuleInSync reports whether an existing PrometheusRule still carries the
+// generated spec and labels
+func prometheusRuleInSync(existing, desired *unstructured.Unstructured) bool {
+	if !equality.Semantic.DeepEqual(existing.Object["spec"], desired.Object["spec"]) {
+		return false
+	}
+
+	existingLabels := existing.GetLabels()
+	for k, v := range desired.GetLabels() {
+		if existingLabels[k] != v {
+			return false
+		}
+	}
+
+	return true
 }
 
 // createPrometheusRule creates a PrometheusRule unstructured object from AlertRule
-func (r *AlertRuleReconciler) createPrometheusRule(alertRule *monitoringv1.AlertRule) *unstructured.Unstructured {
+func (r *AlertRuleReconciler) createPrometheusRule(ctx context.Context, alertRule *monitoringv1.AlertRule) *unstructured.Unstructured {
 	prometheusRule := &unstructured.Unstructured{}
 	prometheusRule.SetGroupVersionKind(prometheusRuleGVK())
 	prometheusRule.SetName(alertRule.Name)
@@ -148,24 +544,22 @@ func (r *AlertRuleReconciler) createPrometheusRule(alertRule *monitoringv1.Alert
 		}
 	}
 	prometheusRule.SetLabels(labels)
+	prometheusRule.SetAnnotations(map[string]string{
+		generationAnnotation: strconv.FormatInt(alertRule.Generation, 10),
+	})
 
 	// OwnerReference 설정
-	ownerRef := metav1.OwnerReference{
-		APIVersion: alertRule.APIVersion,
-		Kind:       alertRule.Kind,
-		Name:       alertRule.Name,
-		UID:        alertRule.UID,
-		Controller: func() *bool { b := true; return &b }(),
+	if err := ctrl.SetControllerReference(alertRule, prometheusRule, r.Scheme); err != nil {
+		logf.Log.Error(err, "unable to set controller reference")
 	}
-	prometheusRule.SetOwnerReferences([]metav1.OwnerReference{ownerRef})
 
 	// PrometheusRule spec 구성
-	groups := []interface{}{
-		map[string]interface{}{
-			"name":  fmt.Sprintf("%s-group", alertRule.Name),
-			"rules": []interface{}{r.buildPrometheusRule(alertRule)},
-		},
+	group := map[string]interface{}{
+		"name":  ruleGroupName(alertRule),
+		"rules": r.buildPrometheusRules(ctx, alertRule),
 	}
+	setGroupSettings(group, alertRule.Spec.GroupSettings)
+	groups := []interface{}{group}
 
 	spec := map[string]interface{}{
 		"groups": groups,
@@ -178,42 +572,200 @@ func (r *AlertRuleReconciler) createPrometheusRule(alertRule *monitoringv1.Alert
 	return prometheusRule
 }
 
-// buildPrometheusRule builds a single Prometheus rule from AlertRule
-func (r *AlertRuleReconciler) buildPrometheusRule(alertRule *monitoringv1.AlertRule) map[string]interface{} {
-	rule := map[string]interface{}{
-		"alert": alertRule.Spec.Alert,
-		"expr":  alertRule.Spec.Expr,
+// buildPrometheusRules builds all Prometheus rules of an AlertRule group.
+// Recording rules are emitted first so that alerting rules of the same group
+// evaluate against series recorded in the same evaluation cycle.
+func (r *AlertRuleReconciler) buildPrometheusRules(ctx context.Context, alertRule *monitoringv1.AlertRule) []interface{} {
+	recordingRules, alertingRules := r.buildRuleSets(ctx, alertRule)
+
+	return append(recordingRules, alertingRules...)
+}
+
+// buildRuleSets builds the recording and the alerting Prometheus rules of an AlertRule.
+// No rules are built for an AlertRule whose templates cannot be rendered or whose
+// namespace isolation cannot be determined or enforced.
+func (r *AlertRuleReconciler) buildRuleSets(ctx context.Context, alertRule *monitoringv1.AlertRule) ([]interface{}, []interface{}) {
+	recordingRules, alertingRules, err := r.ruleSets(ctx, alertRule)
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "unable to build rules", "alertrule", alertRule.Name)
+		return []interface{}{}, []interface{}{}
+	}
+
+	return recordingRules, alertingRules
+}
+
+// unpublishableError explains why the rules of an AlertRule cannot be published until the
+// AlertRule or its environment changes. reason is the reason of its Ready condition.
+type unpublishableError struct {
+	reason string
+	err    error
+}
+
+func (e *unpublishableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *unpublishableError) Unwrap() error {
+	return e.err
+}
+
+// ruleSets builds the recording and the alerting Prometheus rules of an AlertRule. An
+// *unpublishableError is returned when its templates cannot be rendered or one of its rules
+// cannot be restricted to its namespace; other errors are transient.
+func (r *AlertRuleReconciler) ruleSets(ctx context.Context, alertRule *monitoringv1.AlertRule) ([]interface{}, []interface{}, error) {
+	data, err := r.templateData(ctx, alertRule)
+	if err != nil {
+		return nil, nil, err
+	}
+	rendered, err := renderAlertRule(alertRule, data)
+	if err != nil {
+		return nil, nil, &unpublishableError{reason: "RenderFailed", err: err}
+	}
+	alertRule = rendered
+
+	isolated, err := r.namespaceIsolated(ctx, alertRule.Namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	recordingRules := []interface{}{}
+	alertingRules := []interface{}{}
+	for _, rule := range targetRules(alertRule) {
+		// 테넌트 격리 시 AlertRule namespace의 series만 조회하도록 matcher 주입
+		if isolated {
+			enforced, err := enforceNamespace(rule, alertRule.Namespace)
+			if err != nil {
+				name := rule.Alert
+				if name == "" {
+					name = rule.Record
+				}
+				return nil, nil, &unpublishableError{reason: "IsolationFailed", err: fmt.Errorf("rule %s: %w", name, err)}
+			}
+			rule = enforced
+		}
+
+		if rule.Record != "" {
+			recordingRules = append(recordingRules, r.buildRecordingRule(alertRule, rule))
+			continue
+		}
+		alertingRules = append(alertingRules, r.buildPrometheusRule(alertRule, rule))
+	}
+
+	return recordingRules, alertingRules, nil
+}
+
+// setGroupSettings adds the evaluation settings to a Prometheus rule group. Fields already
+// present in the group are kept, so the first settings applied to a shared group win.
+func setGroupSettings(group map[string]interface{}, settings *monitoringv1.RuleGroupSettings) {
+	if settings == nil {
+		return
 	}
 
-	if alertRule.Spec.For != "" {
-		rule["for"] = alertRule.Spec.For
+	setIfAbsent := func(key string, value interface{}) {
+		if _, ok := group[key]; !ok {
+			group[key] = value
+		}
+	}
+	if settings.Interval != "" {
+		setIfAbsent("interval", settings.Interval)
+	}
+	if settings.Limit != nil {
+		setIfAbsent("limit", int64(*settings.Limit))
+	}
+	if settings.QueryOffset != "" {
+		setIfAbsent("query_offset", settings.QueryOffset)
+	}
+	if settings.PartialResponseStrategy != "" {
+		setIfAbsent("partial_response_strategy", settings.PartialResponseStrategy)
+	}
+}
+
+// ruleGroupName returns the name of the Prometheus rule group of an AlertRule
+func ruleGroupName(alertRule *monitoringv1.AlertRule) string {
+	if alertRule.Spec.Group != "" {
+		return alertRule.Spec.Group
+	}
+
+	return fmt.Sprintf("%s-group", alertRule.Name)
+}
+
+// buildRecordingRule builds a single Prometheus recording rule from a rule of the AlertRule
+func (r *AlertRuleReconciler) buildRecordingRule(alertRule *monitoringv1.AlertRule, rule monitoringv1.Rule) map[string]interface{} {
+	promRule := map[string]interface{}{
+		"record": rule.Record,
+		"expr":   rule.Expr,
+	}
+
+	// recording rule에는 severity, for, annotations를 붙이지 않음
+	labels := map[string]interface{}{}
+	for k, v := range alertRule.Spec.Labels {
+		labels[k] = v
+	}
+	for k, v := range rule.Labels {
+		labels[k] = v
+	}
+	if len(labels) > 0 {
+		promRule["labels"] = labels
+	}
+
+	return promRule
+}
+
+// buildPrometheusRule builds a single Prometheus alerting rule from a rule of the AlertRule
+func (r *AlertRuleReconciler) buildPrometheusRule(alertRule *monitoringv1.AlertRule, rule monitoringv1.Rule) map[string]interface{} {
+	promRule := map[string]interface{}{
+		"alert": rule.Alert,
+		"expr":  rule.Expr,
 	}
 
+	if rule.For != "" {
+		promRule["for"] = rule.For
+	}
+
+	// Spec의 severity, labels를 기본값으로 하고 rule labels로 덮어씀
 	labels := map[string]interface{}{
 		"severity": alertRule.Spec.Severity,
 	}
-	if alertRule.Spec.Labels != nil {
-		for k, v := range alertRule.Spec.Labels {
-			labels[k] = v
-		}
+	for k, v := range alertRule.Spec.Labels {
+		labels[k] = v
+	}
+	for k, v := range rule.Labels {
+		labels[k] = v
 	}
-	rule["labels"] = labels
+	// alert를 AlertRule과 연결하는 label은 사용자가 덮어쓸 수 없음
+	labels[alertRuleLabel] = alertRuleIdentity(alertRule)
+	promRule["labels"] = labels
 
-	if alertRule.Spec.Annotations != nil {
+	if rule.Annotations != nil {
 		annotations := make(map[string]interface{})
-		for k, v := range alertRule.Spec.Annotations {
+		for k, v := range rule.Annotations {
 			annotations[k] = v
 		}
-		rule["annotations"] = annotations
+		promRule["annotations"] = annotations
 	}
 
-	return rule
+	return promRule
+}
+
+// specRules returns the rules declared by an AlertRuleSpec. The single-rule fields
+// are kept for backward compatibility and are emitted before the rules list.
+func specRules(spec *monitoringv1.AlertRuleSpec) []monitoringv1.Rule {
+	var rules []monitoringv1.Rule
+	if spec.Expr != "" {
+		rules = append(rules, monitoringv1.Rule{
+			Alert:       spec.Alert,
+			Expr:        spec.Expr,
+			For:         spec.For,
+			Annotations: spec.Annotations,
+		})
+	}
+
+	return append(rules, spec.Rules...)
 }
 
 // deletePrometheusRule deletes the PrometheusRule associated with an AlertRule
-func (r *AlertRuleReconciler) deletePrometheusRule(ctx context.Context, namespace, alertRuleName string) (ctrl.Result, error) {
+func (r *AlertRuleReconciler) deletePrometheusRule(ctx context.Context, namespace, prometheusRuleName string) error {
 	logger := logf.FromContext(ctx)
-	prometheusRuleName := alertRuleName
 
 	prometheusRule := &unstructured.Unstructured{}
 	prometheusRule.SetGroupVersionKind(prometheusRuleGVK())
@@ -224,71 +776,301 @@ func (r *AlertRuleReconciler) deletePrometheusRule(ctx context.Context, namespac
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			// PrometheusRule이 이미 없으면 스킵
-			return ctrl.Result{}, nil
+			return nil
 		}
 		logger.Error(err, "unable to fetch PrometheusRule for deletion")
-		return ctrl.Result{}, err
+		return err
 	}
 
 	logger.Info("Deleting PrometheusRule for deleted AlertRule", "prometheusrule", prometheusRuleName)
 	if err := r.Delete(ctx, prometheusRule); err != nil {
 		if !apierrors.IsNotFound(err) {
 			logger.Error(err, "unable to delete PrometheusRule")
-			return ctrl.Result{}, err
+			return err
 		}
 	}
 
-	return ctrl.Result{}, nil
+	return nil
 }
 
-// updateStatus updates the AlertRule status
-func (r *AlertRuleReconciler) updateStatus(ctx context.Context, alertRule *monitoringv1.AlertRule) error {
-	// PrometheusRule 존재 여부 확인
-	prometheusRule := &unstructured.Unstructured{}
-	prometheusRule.SetGroupVersionKind(prometheusRuleGVK())
-	prometheusRule.SetName(alertRule.Name)
-	prometheusRule.SetNamespace(alertRule.Namespace)
-
-	err := r.Get(ctx, client.ObjectKey{Namespace: alertRule.Namespace, Name: alertRule.Name}, prometheusRule)
+// updateStatus updates the AlertRule status with the result of publishing its rules. A non-nil
+// conflict records that the server-side apply of the PrometheusRule conflicted with another field manager.
+func (r *AlertRuleReconciler) updateStatus(ctx context.Context, alertRule *monitoringv1.AlertRule, backend RuleBackend,
+	published publishResult, conflict error) error {
+	// 게시된 rule 존재 여부 확인
+	ref, err := backend.Reference(ctx, alertRule)
 
 	// Status 업데이트
 	condition := metav1.Condition{
-		Type:               "PrometheusRuleReady",
+		Type:               "Ready",
 		Status:             metav1.ConditionTrue,
-		Reason:             "PrometheusRuleCreated",
-		Message:            "PrometheusRule has been successfully created",
-		LastTransitionTime: metav1.Now(),
+		Reason:             "RulesPublished",
+		Message:            fmt.Sprintf("Rules have been published to the %s backend", backend.Name()),
 		ObservedGeneration: alertRule.Generation,
 	}
 
-	if err != nil {
-		if apierrors.IsNotFound(err) {
-			condition.Status = metav1.ConditionFalse
-			condition.Reason = "PrometheusRuleNotFound"
-			condition.Message = "PrometheusRule not found"
-		} else {
-			condition.Status = metav1.ConditionUnknown
-			condition.Reason = "Error"
-			condition.Message = fmt.Sprintf("Error checking PrometheusRule: %v", err)
-		}
+	switch {
+	case conflict != nil:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "ApplyConflict"
+		condition.Message = fmt.Sprintf("PrometheusRule fields are managed by another field manager: %v", conflict)
+	case err != nil:
+		condition.Status = metav1.ConditionUnknown
+		condition.Reason = "Error"
+		condition.Message = fmt.Sprintf("Error checking published rules: %v", err)
+	case ref == nil:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "RulesNotFound"
+		condition.Message = "Published rules not found"
 	}
 
-	// 기존 조건 업데이트 또는 추가
-	found := false
-	for i, c := range alertRule.Status.Conditions {
-		if c.Type == condition.Type {
-			alertRule.Status.Conditions[i] = condition
-			found = true
-			break
-		}
+	r.setReadyConditions(alertRule, backend, condition)
+	meta.SetStatusCondition(&alertRule.Status.Conditions, metav1.Condition{
+		Type:               "BackendUnavailable",
+		Status:             metav1.ConditionFalse,
+		Reason:             "BackendAvailable",
+		Message:            fmt.Sprintf("The %s backend is available", backend.Name()),
+		ObservedGeneration: alertRule.Generation,
+	})
+	meta.SetStatusCondition(&alertRule.Status.Conditions, metav1.Condition{
+		Type:               "RenderFailed",
+		Status:             metav1.ConditionFalse,
+		Reason:             "TemplatesRendered",
+		Message:            "The templates of the AlertRule have been rendered",
+		ObservedGeneration: alertRule.Generation,
+	})
+
+	if published.drift != "" {
+		meta.SetStatusCondition(&alertRule.Status.Conditions, metav1.Condition{
+			Type:               "DriftCorrected",
+			Status:             metav1.ConditionTrue,
+			Reason:             "ManualChangeReverted",
+			Message:            published.drift,
+			ObservedGeneration: alertRule.Generation,
+		})
 	}
-	if !found {
-		alertRule.Status.Conditions = append(alertRule.Status.Conditions, condition)
+
+	alertRule.Status.ObservedGeneration = alertRule.Generation
+
+	// 충돌로 게시하지 못했다면 이전 backend와 그룹의 rule을 아직 정리하지 않았으므로 유지
+	if conflict == nil {
+		alertRule.Status.RuleHash = published.ruleHash
+		alertRule.Status.PublishedBackend = backend.Name()
+		alertRule.Status.RuleGroup = ruleGroupName(alertRule)
+	}
+
+	// 생성된 객체 참조와 동기화 시각 기록
+	alertRule.Status.GeneratedRef = ref
+	if condition.Status == metav1.ConditionTrue {
+		now := metav1.Now()
+		alertRule.Status.LastSyncTime = &now
 	}
 
 	return r.Status().Update(ctx, alertRule)
 }
 
+// updateBackendUnavailableStatus records in the AlertRule status that its backend cannot accept rules
+func (r *AlertRuleReconciler) updateBackendUnavailableStatus(ctx context.Context, alertRule *monitoringv1.AlertRule, backend RuleBackend, reason string) error {
+	meta.SetStatusCondition(&alertRule.Status.Conditions, metav1.Condition{
+		Type:               "BackendUnavailable",
+		Status:             metav1.ConditionTrue,
+		Reason:             backend.Name() + "Unavailable",
+		Message:            reason,
+		ObservedGeneration: alertRule.Generation,
+	})
+	r.setReadyConditions(alertRule, backend, metav1.Condition{
+		Type:               "Ready",
+		Status:             metav1.ConditionFalse,
+		Reason:             "BackendUnavailable",
+		Message:            fmt.Sprintf("Rules cannot be published until the %s backend is available", backend.Name()),
+		ObservedGeneration: alertRule.Generation,
+	})
+
+	// 게시하지 않은 rule의 해시는 기록하지 않음
+	alertRule.Status.ObservedGeneration = alertRule.Generation
+	alertRule.Status.GeneratedRef = nil
+
+	return r.Status().Update(ctx, alertRule)
+}
+
+// updateRenderFailedStatus records in the AlertRule status that its templates cannot be rendered
+func (r *AlertRuleReconciler) updateRenderFailedStatus(ctx context.Context, alertRule *monitoringv1.AlertRule, backend RuleBackend, renderErr error) error {
+	meta.SetStatusCondition(&alertRule.Status.Conditions, metav1.Condition{
+		Type:               "RenderFailed",
+		Status:             metav1.ConditionTrue,
+		Reason:             "TemplateError",
+		Message:            renderErr.Error(),
+		ObservedGeneration: alertRule.Generation,
+	})
+	r.setReadyConditions(alertRule, backend, metav1.Condition{
+		Type:               "Ready",
+		Status:             metav1.ConditionFalse,
+		Reason:             "RenderFailed",
+		Message:            "Rules cannot be published until the templates of the AlertRule render",
+		ObservedGeneration: alertRule.Generation,
+	})
+
+	alertRule.Status.ObservedGeneration = alertRule.Generation
+
+	return r.Status().Update(ctx, alertRule)
+}
+
+// updateIsolationFailedStatus records in the AlertRule status that a rule could not be restricted to its namespace
+func (r *AlertRuleReconciler) updateIsolationFailedStatus(ctx context.Context, alertRule *monitoringv1.AlertRule, backend RuleBackend, failure string) error {
+	r.setReadyConditions(alertRule, backend, metav1.Condition{
+		Type:               "Ready",
+		Status:             metav1.ConditionFalse,
+		Reason:             "IsolationFailed",
+		Message:            fmt.Sprintf("Rules cannot be published until they can be restricted to the namespace: %s", failure),
+		ObservedGeneration: alertRule.Generation,
+	})
+
+	alertRule.Status.ObservedGeneration = alertRule.Generation
+
+	return r.Status().Update(ctx, alertRule)
+}
+
+// updateTestsFailedStatus records in the AlertRule status that its rule tests failed
+func (r *AlertRuleReconciler) updateTestsFailedStatus(ctx context.Context, alertRule *monitoringv1.AlertRule, backend RuleBackend, message string) error {
+	meta.SetStatusCondition(&alertRule.Status.Conditions, metav1.Condition{
+		Type:               "TestsPassed",
+		Status:             metav1.ConditionFalse,
+		Reason:             "TestsFailed",
+		Message:            message,
+		ObservedGeneration: alertRule.Generation,
+	})
+	r.setReadyConditions(alertRule, backend, metav1.Condition{
+		Type:               "Ready",
+		Status:             metav1.ConditionFalse,
+		Reason:             "TestsFailed",
+		Message:            "Rules are not published until the tests of the AlertRule pass",
+		ObservedGeneration: alertRule.Generation,
+	})
+
+	alertRule.Status.ObservedGeneration = alertRule.Generation
+
+	return r.Status().Update(ctx, alertRule)
+}
+
+// reportUnpublished records in the Ready condition of an AlertRule left out of a shared rule group
+// or rule file why its rules are not published. A Ready condition already reporting the reason is kept.
+func (r *AlertRuleReconciler) reportUnpublished(ctx context.Context, alertRule *monitoringv1.AlertRule, unpublishable *unpublishableError) error {
+	ready := meta.FindStatusCondition(alertRule.Status.Conditions, "Ready")
+	if ready != nil && ready.Status == metav1.ConditionFalse && ready.Reason == unpublishable.reason &&
+		ready.ObservedGeneration == alertRule.Generation {
+		return nil
+	}
+
+	logf.FromContext(ctx).Info("Rules of AlertRule are not published", "alertrule", alertRule.Name,
+		"reason", unpublishable.reason, "error", unpublishable.Error())
+
+	patch := client.MergeFromWithOptions(alertRule.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	r.setReadyConditions(alertRule, r.backendFor(alertRule), metav1.Condition{
+		Type:               "Ready",
+		Status:             metav1.ConditionFalse,
+		Reason:             unpublishable.reason,
+		Message:            fmt.Sprintf("Rules are not published: %s", unpublishable.Error()),
+		ObservedGeneration: alertRule.Generation,
+	})
+
+	return r.Status().Patch(ctx, alertRule, patch)
+}
+
+// setTestsPassedCondition records that the rule tests of an AlertRule passed. The condition
+// is removed from AlertRules without tests.
+func (r *AlertRuleReconciler) setTestsPassedCondition(alertRule *monitoringv1.AlertRule) {
+	if len(alertRule.Spec.Tests) == 0 {
+		meta.RemoveStatusCondition(&alertRule.Status.Conditions, "TestsPassed")
+		return
+	}
+
+	meta.SetStatusCondition(&alertRule.Status.Conditions, metav1.Condition{
+		Type:               "TestsPassed",
+		Status:             metav1.ConditionTrue,
+		Reason:             "TestsPassed",
+		Message:            fmt.Sprintf("All %d tests passed", len(alertRule.Spec.Tests)),
+		ObservedGeneration: alertRule.Generation,
+	})
+}
+
+// setReadyConditions sets the Ready condition. The PrometheusRuleReady condition is kept
+// for AlertRules published as PrometheusRules and removed for the other backends.
+func (r *AlertRuleReconciler) setReadyConditions(alertRule *monitoringv1.AlertRule, backend RuleBackend, ready metav1.Condition) {
+	meta.SetStatusCondition(&alertRule.Status.Conditions, ready)
+
+	if backend.Name() != monitoringv1.BackendPrometheusRule {
+		meta.RemoveStatusCondition(&alertRule.Status.Conditions, "PrometheusRuleReady")
+		return
+	}
+
+	condition := ready
+	condition.Type = "PrometheusRuleReady"
+	switch ready.Reason {
+	case "RulesPublished":
+		condition.Reason = "PrometheusRuleCreated"
+		condition.Message = "PrometheusRule has been successfully created"
+	case "RulesNotFound":
+		condition.Reason = "PrometheusRuleNotFound"
+		condition.Message = "PrometheusRule not found"
+	}
+	meta.SetStatusCondition(&alertRule.Status.Conditions, condition)
+}
+
+// prometheusRuleCRDAvailable reports whether the PrometheusRule CRD is served by the API server
+func (r *AlertRuleReconciler) prometheusRuleCRDAvailable() (bool, error) {
+	gvk := prometheusRuleGVK()
+	if _, err := r.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+		if meta.IsNoMatchError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// alertRulesForCRD enqueues every AlertRule when the PrometheusRule or AlertmanagerConfig CRD changes,
+// so AlertRules reconciled while the CRD was missing are retried once it is installed, and starts
+// watching the generated objects of a newly installed CRD
+func (r *AlertRuleReconciler) alertRulesForCRD(ctx context.Context, _ client.Object) []reconcile.Request {
+	r.watches.ensure(ctx)
+
+	alertRules := &monitoringv1.AlertRuleList{}
+	if err := r.List(ctx, alertRules); err != nil {
+		logf.FromContext(ctx).Error(err, "unable to list AlertRules")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(alertRules.Items))
+	for _, alertRule := range alertRules.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: client.ObjectKeyFromObject(&alertRule),
+		})
+	}
+
+	return requests
+}
+
+// ruleHash returns a content hash of the rule groups of a generated PrometheusRule
+func ruleHash(prometheusRule *unstructured.Unstructured) string {
+	groups, _, _ := unstructured.NestedFieldNoCopy(prometheusRule.Object, "spec", "groups")
+	slice, _ := groups.([]interface{})
+
+	return groupsHash(slice)
+}
+
+// groupsHash returns a content hash of Prometheus rule groups
+func groupsHash(groups []interface{}) string {
+	// map 키는 정렬되어 직렬화되므로 같은 규칙은 항상 같은 해시를 가짐
+	data, err := json.Marshal(groups)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])[:16]
+}
+
 // prometheusRuleGVK returns the GroupVersionKind for PrometheusRule
 func prometheusRuleGVK() schema.GroupVersionKind {
 	return schema.GroupVersionKind{
@@ -300,8 +1082,65 @@ func prometheusRuleGVK() schema.GroupVersionKind {
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *AlertRuleReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&monitoringv1.AlertRule{}).
-		Named("alertrule").
-		Complete(r)
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&monitoringv1.AlertRule{})
+
+	// WorkloadSelector가 선택하거나 템플릿이 참조하는 workload가 바뀌면 다시 reconcile
+	for _, kind := range SupportedWorkloadKinds {
+		b = b.Watches(workloadKinds[kind].newObject(),
+			handler.EnqueueRequestsFromMapFunc(r.alertRulesSelecting(kind)),
+			builder.WithPredicates(predicate.Or(predicate.LabelChangedPredicate{}, predicate.AnnotationChangedPredicate{})))
+	}
+
+	// Namespace의 테넌트 격리 설정이 바뀌면 그 namespace의 AlertRule을 다시 reconcile
+	b = b.Watches(&corev1.Namespace{},
+		handler.EnqueueRequestsFromMapFunc(r.alertRulesInNamespace),
+		builder.WithPredicates(predicate.AnnotationChangedPredicate{}))
+
+	// PrometheusRule 또는 AlertmanagerConfig CRD가 설치되면 모든 AlertRule을 다시 reconcile
+	crd := &metav1.PartialObjectMetadata{}
+	crd.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   "apiextensions.k8s.io",
+		Version: "v1",
+		Kind:    "CustomResourceDefinition",
+	})
+	b = b.Watches(crd,
+		handler.EnqueueRequestsFromMapFunc(r.alertRulesForCRD),
+		builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			return obj.GetName() == prometheusRuleCRDName || obj.GetName() == alertmanagerConfigCRDName
+		})))
+
+	// Prometheus가 설정된 경우에만 expression과 alert 상태를 주기적으로 조회
+	if r.Prometheus.URL != "" || r.Prometheus.AlertsURL != "" {
+		if err := mgr.Add(manager.RunnableFunc(r.pollPrometheus)); err != nil {
+			return err
+		}
+	}
+
+	// Alertmanager가 설정된 경우에만 유지보수 시간의 silence를 주기적으로 동기화
+	if r.Silences.URL != "" {
+		if err := mgr.Add(manager.RunnableFunc(r.pollSilences)); err != nil {
+			return err
+		}
+	}
+
+	c, err := b.Named("alertrule").Build(r)
+	if err != nil {
+		return err
+	}
+
+	// 생성한 PrometheusRule과 AlertmanagerConfig의 변경은 CRD가 설치된 뒤부터 감시
+	r.watches = newGeneratedWatches(c, mgr.GetCache(), mgr.GetRESTMapper())
+	if r.Aggregate {
+		// 통합된 PrometheusRule은 controller 없이 모든 AlertRule이 소유
+		r.watches.add(prometheusRuleGVK(), handler.EnqueueRequestsFromMapFunc(alertRulesOwning))
+	} else {
+		r.watches.add(prometheusRuleGVK(), handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(),
+			&monitoringv1.AlertRule{}, handler.OnlyControllerOwner()))
+	}
+	r.watches.add(alertmanagerConfigGVK(), handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(),
+		&monitoringv1.AlertRule{}, handler.OnlyControllerOwner()))
+	r.watches.ensure(ctrl.LoggerInto(context.Background(), mgr.GetLogger()))
+
+	return nil
 }
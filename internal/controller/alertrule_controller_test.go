@@ -18,14 +18,31 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	monitoringv1 "github.com/Kim-Yukyung/k8s-alert-rule-operator/api/v1"
 )
@@ -72,8 +89,9 @@ var _ = Describe("AlertRule Controller", func() {
 		It("should successfully reconcile the resource", func() {
 			By("Reconciling the created resource")
 			controllerReconciler := &AlertRuleReconciler{
-				Client: k8sClient,
-				Scheme: k8sClient.Scheme(),
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(10),
 			}
 
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
@@ -84,4 +102,1263 @@ var _ = Describe("AlertRule Controller", func() {
 			// Example: If you expect a certain status condition after reconciliation, verify it here.
 		})
 	})
+
+	Context("When building a PrometheusRule", func() {
+		It("should emit the single rule and the rules list in the same group", func() {
+			alertRule := &monitoringv1.AlertRule{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "checkout",
+					Namespace: "default",
+				},
+				Spec: monitoringv1.AlertRuleSpec{
+					Alert:    "CheckoutDown",
+					Expr:     `up{job="checkout"} == 0`,
+					Severity: "critical",
+					Labels:   map[string]string{"team": "payments"},
+					Rules: []monitoringv1.Rule{
+						{
+							Alert:  "CheckoutHighLatency",
+							Expr:   `histogram_quantile(0.99, rate(http_request_duration_seconds_bucket{job="checkout"}[5m])) > 1`,
+							For:    "10m",
+							Labels: map[string]string{"severity": "warning"},
+						},
+					},
+				},
+			}
+
+			reconciler := &AlertRuleReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			prometheusRule := reconciler.createPrometheusRule(ctx, alertRule)
+
+			groups, found, err := unstructured.NestedSlice(prometheusRule.Object, "spec", "groups")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(found).To(BeTrue())
+			Expect(groups).To(HaveLen(1))
+
+			rules := groups[0].(map[string]interface{})["rules"].([]interface{})
+			Expect(rules).To(HaveLen(2))
+
+			first := rules[0].(map[string]interface{})
+			Expect(first["alert"]).To(Equal("CheckoutDown"))
+			Expect(first["labels"]).To(HaveKeyWithValue("severity", "critical"))
+			Expect(first["labels"]).To(HaveKeyWithValue("team", "payments"))
+			Expect(first["labels"]).To(HaveKeyWithValue("alertrule", "default/checkout"))
+
+			second := rules[1].(map[string]interface{})
+			Expect(second["alert"]).To(Equal("CheckoutHighLatency"))
+			Expect(second["for"]).To(Equal("10m"))
+			Expect(second["labels"]).To(HaveKeyWithValue("severity", "warning"))
+			Expect(second["labels"]).To(HaveKeyWithValue("team", "payments"))
+		})
+
+		It("should emit recording rules before the alerts referencing them", func() {
+			alertRule := &monitoringv1.AlertRule{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "slo",
+					Namespace: "default",
+				},
+				Spec: monitoringv1.AlertRuleSpec{
+					Severity: "warning",
+					Labels:   map[string]string{"team": "payments"},
+					Rules: []monitoringv1.Rule{
+						{
+							Alert: "ErrorBudgetBurn",
+							Expr:  "job:http_errors:ratio_rate5m > 0.01",
+							For:   "5m",
+						},
+						{
+							Record: "job:http_errors:ratio_rate5m",
+							Expr:   `sum by (job) (rate(http_requests_total{code=~"5.."}[5m])) / sum by (job) (rate(http_requests_total[5m]))`,
+						},
+					},
+				},
+			}
+
+			reconciler := &AlertRuleReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			prometheusRule := reconciler.createPrometheusRule(ctx, alertRule)
+
+			groups, _, err := unstructured.NestedSlice(prometheusRule.Object, "spec", "groups")
+			Expect(err).NotTo(HaveOccurred())
+			rules := groups[0].(map[string]interface{})["rules"].([]interface{})
+			Expect(rules).To(HaveLen(2))
+
+			recording := rules[0].(map[string]interface{})
+			Expect(recording["record"]).To(Equal("job:http_errors:ratio_rate5m"))
+			Expect(recording).NotTo(HaveKey("for"))
+			Expect(recording).NotTo(HaveKey("annotations"))
+			Expect(recording["labels"]).To(Equal(map[string]interface{}{"team": "payments"}))
+
+			alerting := rules[1].(map[string]interface{})
+			Expect(alerting["alert"]).To(Equal("ErrorBudgetBurn"))
+			Expect(alerting["labels"]).To(HaveKeyWithValue("severity", "warning"))
+		})
+	})
+
+	Context("When configuring the rule group", func() {
+		It("should emit the group settings", func() {
+			limit := int32(10)
+			alertRule := &monitoringv1.AlertRule{
+				ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "default"},
+				Spec: monitoringv1.AlertRuleSpec{
+					Alert: "CheckoutDown",
+					Expr:  `up{job="checkout"} == 0`,
+					Group: "checkout",
+					GroupSettings: &monitoringv1.RuleGroupSettings{
+						Interval:                "30s",
+						Limit:                   &limit,
+						QueryOffset:             "1m",
+						PartialResponseStrategy: "warn",
+					},
+				},
+			}
+
+			reconciler := &AlertRuleReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			prometheusRule := reconciler.createPrometheusRule(ctx, alertRule)
+
+			groups, _, err := unstructured.NestedSlice(prometheusRule.Object, "spec", "groups")
+			Expect(err).NotTo(HaveOccurred())
+			group := groups[0].(map[string]interface{})
+			Expect(group).To(HaveKeyWithValue("name", "checkout"))
+			Expect(group).To(HaveKeyWithValue("interval", "30s"))
+			Expect(group).To(HaveKeyWithValue("limit", int64(10)))
+			Expect(group).To(HaveKeyWithValue("query_offset", "1m"))
+			Expect(group).To(HaveKeyWithValue("partial_response_strategy", "warn"))
+		})
+
+		It("should apply the first settings by name to an aggregated group", func() {
+			alertRules := []monitoringv1.AlertRule{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "default", UID: "uid-b"},
+					Spec: monitoringv1.AlertRuleSpec{
+						Alert:         "B",
+						Expr:          "up == 0",
+						Group:         "shared",
+						GroupSettings: &monitoringv1.RuleGroupSettings{Interval: "1m", QueryOffset: "30s"},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "default", UID: "uid-a"},
+					Spec: monitoringv1.AlertRuleSpec{
+						Alert:         "A",
+						Expr:          "up == 0",
+						Group:         "shared",
+						GroupSettings: &monitoringv1.RuleGroupSettings{Interval: "15s"},
+					},
+				},
+			}
+
+			reconciler := &AlertRuleReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), Aggregate: true}
+			prometheusRule, _, err := reconciler.buildAggregatedPrometheusRule(ctx, "default", alertRules)
+			Expect(err).NotTo(HaveOccurred())
+
+			groups, _, err := unstructured.NestedSlice(prometheusRule.Object, "spec", "groups")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(groups).To(HaveLen(1))
+			group := groups[0].(map[string]interface{})
+			Expect(group).To(HaveKeyWithValue("interval", "15s"))
+			Expect(group).To(HaveKeyWithValue("query_offset", "30s"))
+		})
+	})
+
+	Context("When checking a PrometheusRule for manual changes", func() {
+		var desired *unstructured.Unstructured
+
+		BeforeEach(func() {
+			alertRule := &monitoringv1.AlertRule{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "checkout",
+					Namespace:  "default",
+					Generation: 2,
+				},
+				Spec: monitoringv1.AlertRuleSpec{
+					Alert:    "CheckoutDown",
+					Expr:     `up{job="checkout"} == 0`,
+					Severity: "critical",
+				},
+			}
+
+			reconciler := &AlertRuleReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			desired = reconciler.createPrometheusRule(ctx, alertRule)
+		})
+
+		It("should record the AlertRule generation and owner", func() {
+			Expect(desired.GetAnnotations()).To(HaveKeyWithValue(generationAnnotation, "2"))
+			Expect(desired.GetOwnerReferences()).To(HaveLen(1))
+			Expect(desired.GetOwnerReferences()[0].Kind).To(Equal("AlertRule"))
+		})
+
+		It("should hash the emitted rules only", func() {
+			existing := desired.DeepCopy()
+			existing.SetAnnotations(map[string]string{generationAnnotation: "3"})
+			Expect(ruleHash(existing)).To(Equal(ruleHash(desired)))
+
+			groups, _, err := unstructured.NestedSlice(existing.Object, "spec", "groups")
+			Expect(err).NotTo(HaveOccurred())
+			groups[0].(map[string]interface{})["name"] = "other-group"
+			Expect(unstructured.SetNestedSlice(existing.Object, groups, "spec", "groups")).To(Succeed())
+			Expect(ruleHash(existing)).NotTo(Equal(ruleHash(desired)))
+		})
+
+		It("should treat added labels as in sync", func() {
+			existing := desired.DeepCopy()
+			labels := existing.GetLabels()
+			labels["extra"] = "value"
+			existing.SetLabels(labels)
+
+			Expect(prometheusRuleInSync(existing, desired)).To(BeTrue())
+		})
+
+		It("should detect an edited rule expression", func() {
+			existing := desired.DeepCopy()
+			groups, _, err := unstructured.NestedSlice(existing.Object, "spec", "groups")
+			Expect(err).NotTo(HaveOccurred())
+			rules := groups[0].(map[string]interface{})["rules"].([]interface{})
+			rules[0].(map[string]interface{})["expr"] = "vector(1)"
+			Expect(unstructured.SetNestedSlice(existing.Object, groups, "spec", "groups")).To(Succeed())
+
+			Expect(prometheusRuleInSync(existing, desired)).To(BeFalse())
+		})
+
+		It("should detect a removed operator label", func() {
+			existing := desired.DeepCopy()
+			labels := existing.GetLabels()
+			delete(labels, "managed-by")
+			existing.SetLabels(labels)
+
+			Expect(prometheusRuleInSync(existing, desired)).To(BeFalse())
+		})
+	})
+
+	Context("When the server-side apply conflicts", func() {
+		It("should report the conflict in the status instead of failing", func() {
+			alertRule := &monitoringv1.AlertRule{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "checkout",
+					Namespace:  "default",
+					Generation: 2,
+				},
+				Spec: monitoringv1.AlertRuleSpec{
+					Alert:    "CheckoutDown",
+					Expr:     `up{job="checkout"} == 0`,
+					Severity: "critical",
+				},
+			}
+
+			var applyOpts client.ApplyOptions
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(k8sClient.Scheme()).
+				WithRESTMapper(newTestRESTMapper(true)).
+				WithObjects(alertRule).
+				WithStatusSubresource(alertRule).
+				WithInterceptorFuncs(interceptor.Funcs{
+					// 이전 generation으로 생성된 PrometheusRule이 있는 것처럼 응답
+					Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+						if u, ok := obj.(*unstructured.Unstructured); ok {
+							u.SetAnnotations(map[string]string{generationAnnotation: "1"})
+							return nil
+						}
+						return c.Get(ctx, key, obj, opts...)
+					},
+					Apply: func(ctx context.Context, c client.WithWatch, obj runtime.ApplyConfiguration, opts ...client.ApplyOption) error {
+						applyOpts.ApplyOptions(opts)
+						return newApplyConflict("checkout", "argocd-controller")
+					},
+				}).
+				Build()
+
+			reconciler := &AlertRuleReconciler{
+				Client:   fakeClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(10),
+			}
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: "checkout", Namespace: "default"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(applyOpts.FieldManager).To(Equal(fieldManager))
+			Expect(applyOpts.Force).To(BeNil())
+
+			updated := &monitoringv1.AlertRule{}
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "checkout", Namespace: "default"}, updated)).To(Succeed())
+			condition := meta.FindStatusCondition(updated.Status.Conditions, "PrometheusRuleReady")
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("ApplyConflict"))
+			Expect(condition.Message).To(ContainSubstring("argocd-controller"))
+
+			Expect(updated.Status.ObservedGeneration).To(Equal(int64(2)))
+			Expect(updated.Status.RuleHash).To(BeEmpty())
+			Expect(updated.Status.GeneratedRef).NotTo(BeNil())
+			Expect(updated.Status.GeneratedRef.Kind).To(Equal("PrometheusRule"))
+			Expect(updated.Status.LastSyncTime).To(BeNil())
+		})
+
+		It("should retry conflicts that are not caused by another field manager", func() {
+			alertRule := &monitoringv1.AlertRule{
+				ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "default"},
+				Spec: monitoringv1.AlertRuleSpec{
+					Alert:    "CheckoutDown",
+					Expr:     `up{job="checkout"} == 0`,
+					Severity: "critical",
+				},
+			}
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(k8sClient.Scheme()).
+				WithRESTMapper(newTestRESTMapper(true)).
+				WithObjects(alertRule).
+				WithStatusSubresource(alertRule).
+				WithInterceptorFuncs(interceptor.Funcs{
+					// managed fields를 이전하는 동안 PrometheusRule이 바뀐 것처럼 응답
+					Apply: func(ctx context.Context, c client.WithWatch, obj runtime.ApplyConfiguration, opts ...client.ApplyOption) error {
+						return errors.NewConflict(schema.GroupResource{Group: "monitoring.coreos.com", Resource: "prometheusrules"},
+							"checkout", fmt.Errorf("the object has been modified; please apply your changes to the latest version and try again"))
+					},
+				}).
+				Build()
+			reconciler := &AlertRuleReconciler{Client: fakeClient, Scheme: k8sClient.Scheme(), Recorder: record.NewFakeRecorder(10)}
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: "checkout", Namespace: "default"},
+			})
+			Expect(errors.IsConflict(err)).To(BeTrue())
+
+			updated := &monitoringv1.AlertRule{}
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "checkout", Namespace: "default"}, updated)).To(Succeed())
+			Expect(meta.FindStatusCondition(updated.Status.Conditions, "PrometheusRuleReady")).To(BeNil())
+		})
+
+		It("should migrate the fields owned by the former Update field manager before applying", func() {
+			alertRule := &monitoringv1.AlertRule{
+				ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "default"},
+				Spec: monitoringv1.AlertRuleSpec{
+					Alert:    "CheckoutDown",
+					Expr:     `up{job="checkout"} == 0`,
+					Severity: "critical",
+				},
+			}
+
+			var patch []byte
+			var patchType types.PatchType
+			applied := false
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(k8sClient.Scheme()).
+				WithRESTMapper(newTestRESTMapper(true)).
+				WithObjects(alertRule).
+				WithStatusSubresource(alertRule).
+				WithInterceptorFuncs(interceptor.Funcs{
+					// 이전 버전이 Update로 생성한 PrometheusRule이 있는 것처럼 응답
+					Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+						if u, ok := obj.(*unstructured.Unstructured); ok {
+							u.SetName(key.Name)
+							u.SetNamespace(key.Namespace)
+							u.SetManagedFields([]metav1.ManagedFieldsEntry{{
+								Manager:    strings.Split(rest.DefaultKubernetesUserAgent(), "/")[0],
+								Operation:  metav1.ManagedFieldsOperationUpdate,
+								APIVersion: "monitoring.coreos.com/v1",
+								FieldsType: "FieldsV1",
+								FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:spec":{".":{},"f:groups":{}}}`)},
+							}})
+							return nil
+						}
+						return c.Get(ctx, key, obj, opts...)
+					},
+					Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, p client.Patch, opts ...client.PatchOption) error {
+						patchType = p.Type()
+						patch, _ = p.Data(obj)
+						return nil
+					},
+					Apply: func(ctx context.Context, c client.WithWatch, obj runtime.ApplyConfiguration, opts ...client.ApplyOption) error {
+						Expect(patch).NotTo(BeNil())
+						applied = true
+						return nil
+					},
+				}).
+				Build()
+
+			reconciler := &AlertRuleReconciler{
+				Client:   fakeClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(10),
+			}
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: "checkout", Namespace: "default"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(applied).To(BeTrue())
+			Expect(patchType).To(Equal(types.JSONPatchType))
+			Expect(string(patch)).To(ContainSubstring(`"manager":"` + fieldManager + `"`))
+			Expect(string(patch)).To(ContainSubstring(`"operation":"Apply"`))
+		})
+	})
+
+	Context("When the PrometheusRule CRD is not installed", func() {
+		It("should report BackendUnavailable and requeue AlertRules once the CRD appears", func() {
+			alertRule := &monitoringv1.AlertRule{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "checkout",
+					Namespace:  "default",
+					Generation: 1,
+				},
+				Spec: monitoringv1.AlertRuleSpec{
+					Alert: "CheckoutDown",
+					Expr:  `up{job="checkout"} == 0`,
+				},
+			}
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(k8sClient.Scheme()).
+				WithRESTMapper(newTestRESTMapper(false)).
+				WithObjects(alertRule).
+				WithStatusSubresource(alertRule).
+				Build()
+
+			reconciler := &AlertRuleReconciler{
+				Client:   fakeClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(10),
+			}
+
+			key := types.NamespacedName{Name: "checkout", Namespace: "default"}
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(reconcile.Result{}))
+
+			updated := &monitoringv1.AlertRule{}
+			Expect(fakeClient.Get(ctx, key, updated)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, "BackendUnavailable")).To(BeTrue())
+			ready := meta.FindStatusCondition(updated.Status.Conditions, "PrometheusRuleReady")
+			Expect(ready).NotTo(BeNil())
+			Expect(ready.Reason).To(Equal("BackendUnavailable"))
+			Expect(updated.Status.GeneratedRef).To(BeNil())
+
+			Expect(reconciler.alertRulesForCRD(ctx, nil)).To(ConsistOf(reconcile.Request{NamespacedName: key}))
+		})
+
+		It("should start watching the generated PrometheusRules once the CRD appears", func() {
+			mapper := meta.NewDefaultRESTMapper(nil)
+			watching := &watchRecorder{}
+			reconciler := &AlertRuleReconciler{
+				Client:  fake.NewClientBuilder().WithScheme(k8sClient.Scheme()).Build(),
+				Scheme:  k8sClient.Scheme(),
+				watches: newGeneratedWatches(watching, nil, mapper),
+			}
+			reconciler.watches.add(prometheusRuleGVK(), &handler.EnqueueRequestForObject{})
+
+			reconciler.watches.ensure(ctx)
+			Expect(watching.sources).To(BeEmpty())
+
+			By("installing the CRD")
+			mapper.Add(prometheusRuleGVK(), meta.RESTScopeNamespace)
+			reconciler.alertRulesForCRD(ctx, nil)
+			Expect(watching.sources).To(HaveLen(1))
+
+			By("not watching twice")
+			reconciler.alertRulesForCRD(ctx, nil)
+			Expect(watching.sources).To(HaveLen(1))
+		})
+	})
+
+	Context("When aggregating AlertRules of a namespace", func() {
+		It("should merge AlertRules sharing a group and keep the others apart", func() {
+			newAlertRule := func(name, group string, uid types.UID, rule monitoringv1.Rule) monitoringv1.AlertRule {
+				return monitoringv1.AlertRule{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: uid},
+					Spec: monitoringv1.AlertRuleSpec{
+						Severity: "warning",
+						Group:    group,
+						Rules:    []monitoringv1.Rule{rule},
+					},
+				}
+			}
+			alertRules := []monitoringv1.AlertRule{
+				newAlertRule("payments-latency", "payments", "uid-1", monitoringv1.Rule{
+					Alert: "PaymentsHighLatency",
+					Expr:  "job:latency:p99 > 1",
+				}),
+				newAlertRule("cache", "", "uid-2", monitoringv1.Rule{
+					Alert: "CacheDown",
+					Expr:  `up{job="cache"} == 0`,
+				}),
+				newAlertRule("payments-recording", "payments", "uid-3", monitoringv1.Rule{
+					Record: "job:latency:p99",
+					Expr:   "histogram_quantile(0.99, rate(http_request_duration_seconds_bucket[5m]))",
+				}),
+			}
+
+			reconciler := &AlertRuleReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), Aggregate: true}
+			prometheusRule, _, err := reconciler.buildAggregatedPrometheusRule(ctx, "default", alertRules)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(prometheusRule.GetName()).To(Equal(aggregatedPrometheusRuleName))
+			Expect(prometheusRule.GetOwnerReferences()).To(HaveLen(3))
+
+			groups, _, err := unstructured.NestedSlice(prometheusRule.Object, "spec", "groups")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(groups).To(HaveLen(2))
+
+			cache := groups[0].(map[string]interface{})
+			Expect(cache["name"]).To(Equal("cache-group"))
+			Expect(cache["rules"]).To(HaveLen(1))
+
+			payments := groups[1].(map[string]interface{})
+			Expect(payments["name"]).To(Equal("payments"))
+			rules := payments["rules"].([]interface{})
+			Expect(rules).To(HaveLen(2))
+			Expect(rules[0].(map[string]interface{})["record"]).To(Equal("job:latency:p99"))
+			Expect(rules[1].(map[string]interface{})["alert"]).To(Equal("PaymentsHighLatency"))
+
+			Expect(alertRulesOwning(ctx, prometheusRule)).To(ConsistOf(
+				reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "cache"}},
+				reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "payments-latency"}},
+				reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "payments-recording"}},
+			))
+		})
+
+		It("should only merge AlertRules published as PrometheusRules", func() {
+			prometheusRuleAlert := &monitoringv1.AlertRule{
+				ObjectMeta: metav1.ObjectMeta{Name: "cache", Namespace: "default"},
+				Spec:       monitoringv1.AlertRuleSpec{Alert: "CacheDown", Expr: `up{job="cache"} == 0`, Severity: "warning"},
+			}
+			configMapAlert := &monitoringv1.AlertRule{
+				ObjectMeta: metav1.ObjectMeta{Name: "payments", Namespace: "default"},
+				Spec: monitoringv1.AlertRuleSpec{
+					Alert:    "PaymentsDown",
+					Expr:     `up{job="payments"} == 0`,
+					Severity: "warning",
+					Backend:  monitoringv1.BackendConfigMap,
+				},
+			}
+
+			var applied *unstructured.Unstructured
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(k8sClient.Scheme()).
+				WithObjects(prometheusRuleAlert, configMapAlert).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+						if _, ok := obj.(*unstructured.Unstructured); ok {
+							return errors.NewNotFound(schema.GroupResource{Group: "monitoring.coreos.com", Resource: "prometheusrules"}, key.Name)
+						}
+						return c.Get(ctx, key, obj, opts...)
+					},
+					Apply: func(ctx context.Context, c client.WithWatch, obj runtime.ApplyConfiguration, opts ...client.ApplyOption) error {
+						applied = &unstructured.Unstructured{}
+						data, err := json.Marshal(obj)
+						Expect(err).NotTo(HaveOccurred())
+						return json.Unmarshal(data, &applied.Object)
+					},
+				}).
+				Build()
+
+			reconciler := &AlertRuleReconciler{Client: fakeClient, Scheme: k8sClient.Scheme(), Aggregate: true}
+			_, _, err := reconciler.reconcileAggregatedPrometheusRule(ctx, "default")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(applied).NotTo(BeNil())
+			Expect(applied.GetOwnerReferences()).To(HaveLen(1))
+			Expect(applied.GetOwnerReferences()[0].Name).To(Equal("cache"))
+			groups, _, err := unstructured.NestedSlice(applied.Object, "spec", "groups")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(groups).To(HaveLen(1))
+			Expect(groups[0].(map[string]interface{})["name"]).To(Equal("cache-group"))
+		})
+		It("should revert manual changes of the aggregated PrometheusRule", func() {
+			alertRule := &monitoringv1.AlertRule{
+				ObjectMeta: metav1.ObjectMeta{Name: "cache", Namespace: "default", UID: "uid-1", Generation: 1},
+				Spec:       monitoringv1.AlertRuleSpec{Alert: "CacheDown", Expr: `up{job="cache"} == 0`, Severity: "warning"},
+				Status: monitoringv1.AlertRuleStatus{
+					Conditions: []metav1.Condition{{
+						Type:               "PrometheusRuleReady",
+						Status:             metav1.ConditionTrue,
+						Reason:             "Synced",
+						ObservedGeneration: 1,
+					}},
+				},
+			}
+
+			var applyOpts client.ApplyOptions
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(k8sClient.Scheme()).
+				WithObjects(alertRule).
+				WithInterceptorFuncs(interceptor.Funcs{
+					// AlertRule이 바뀌지 않았는데 rule이 지워진 PrometheusRule
+					Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+						if u, ok := obj.(*unstructured.Unstructured); ok {
+							u.SetAnnotations(map[string]string{generationAnnotation: aggregatedGeneration([]monitoringv1.AlertRule{*alertRule})})
+							return unstructured.SetNestedSlice(u.Object, []interface{}{}, "spec", "groups")
+						}
+						return c.Get(ctx, key, obj, opts...)
+					},
+					Apply: func(ctx context.Context, c client.WithWatch, obj runtime.ApplyConfiguration, opts ...client.ApplyOption) error {
+						applyOpts.ApplyOptions(opts)
+						return nil
+					},
+				}).
+				Build()
+
+			reconciler := &AlertRuleReconciler{Client: fakeClient, Scheme: k8sClient.Scheme(), Aggregate: true}
+			drift, _, err := reconciler.reconcileAggregatedPrometheusRule(ctx, "default")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(drift).To(ContainSubstring("modified manually"))
+			Expect(applyOpts.Force).To(HaveValue(BeTrue()))
+		})
+
+		It("should report AlertRules left out of the aggregated PrometheusRule", func() {
+			isolated := &monitoringv1.AlertRule{
+				ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "team-a", Generation: 1},
+				Spec:       monitoringv1.AlertRuleSpec{Alert: "ApiDown", Expr: `up{job="api"} == 0`},
+			}
+			broken := &monitoringv1.AlertRule{
+				ObjectMeta: metav1.ObjectMeta{Name: "errors", Namespace: "team-a", Generation: 1},
+				Spec:       monitoringv1.AlertRuleSpec{Alert: "ApiErrors", Expr: `rate(http_requests_total{code=~"5.."}[5m] > 1`},
+			}
+
+			var applied *unstructured.Unstructured
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(k8sClient.Scheme()).
+				WithObjects(isolated, broken, newNamespace("team-a", monitoringv1.TenantIsolationEnforced)).
+				WithStatusSubresource(isolated, broken).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+						if _, ok := obj.(*unstructured.Unstructured); ok {
+							return errors.NewNotFound(schema.GroupResource{Group: "monitoring.coreos.com", Resource: "prometheusrules"}, key.Name)
+						}
+						return c.Get(ctx, key, obj, opts...)
+					},
+					Apply: func(ctx context.Context, c client.WithWatch, obj runtime.ApplyConfiguration, opts ...client.ApplyOption) error {
+						applied = &unstructured.Unstructured{}
+						data, err := json.Marshal(obj)
+						Expect(err).NotTo(HaveOccurred())
+						return json.Unmarshal(data, &applied.Object)
+					},
+				}).
+				Build()
+
+			reconciler := &AlertRuleReconciler{Client: fakeClient, Scheme: k8sClient.Scheme(), Aggregate: true}
+			_, _, err := reconciler.reconcileAggregatedPrometheusRule(ctx, "team-a")
+			Expect(err).NotTo(HaveOccurred())
+
+			groups, _, err := unstructured.NestedSlice(applied.Object, "spec", "groups")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(groups).To(HaveLen(1))
+			Expect(groups[0].(map[string]interface{})["name"]).To(Equal("api-group"))
+
+			updated := &monitoringv1.AlertRule{}
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(broken), updated)).To(Succeed())
+			ready := meta.FindStatusCondition(updated.Status.Conditions, "Ready")
+			Expect(ready).NotTo(BeNil())
+			Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+			Expect(ready.Reason).To(Equal("IsolationFailed"))
+			Expect(ready.Message).To(ContainSubstring("rule ApiErrors"))
+		})
+	})
+
+	Context("When targeting workloads by label selector", func() {
+		var (
+			alertRule  *monitoringv1.AlertRule
+			fakeClient client.Client
+			reconciler *AlertRuleReconciler
+		)
+
+		BeforeEach(func() {
+			alertRule = &monitoringv1.AlertRule{
+				ObjectMeta: metav1.ObjectMeta{Name: "frontend", Namespace: "default"},
+				Spec: monitoringv1.AlertRuleSpec{
+					Alert: "FrontendUnavailable",
+					Expr:  `kube_deployment_status_replicas_unavailable > 0`,
+					WorkloadSelector: &monitoringv1.WorkloadSelector{
+						Selector: metav1.LabelSelector{MatchLabels: map[string]string{"tier": "frontend"}},
+					},
+				},
+			}
+			fakeClient = fake.NewClientBuilder().
+				WithScheme(k8sClient.Scheme()).
+				WithObjects(alertRule,
+					newTestDeployment("web", map[string]string{"tier": "frontend"}),
+					newTestDeployment("shop", map[string]string{"tier": "frontend"}),
+					newTestDeployment("db", map[string]string{"tier": "backend"})).
+				WithStatusSubresource(alertRule).
+				Build()
+			reconciler = &AlertRuleReconciler{Client: fakeClient, Scheme: k8sClient.Scheme()}
+		})
+
+		It("should record the matched workloads and follow them as they come and go", func() {
+			Expect(reconciler.updateMatchedWorkloads(ctx, alertRule)).To(Succeed())
+			Expect(alertRule.Status.MatchedWorkloads).To(Equal([]string{"shop", "web"}))
+
+			Expect(fakeClient.Create(ctx, newTestDeployment("admin", map[string]string{"tier": "frontend"}))).To(Succeed())
+			Expect(reconciler.alertRulesSelecting("Deployment")(ctx, newTestDeployment("admin", nil))).To(ConsistOf(
+				reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "frontend"}},
+			))
+			Expect(reconciler.alertRulesSelecting("StatefulSet")(ctx, newTestDeployment("admin", nil))).To(BeEmpty())
+
+			Expect(reconciler.updateMatchedWorkloads(ctx, alertRule)).To(Succeed())
+			updated := &monitoringv1.AlertRule{}
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(alertRule), updated)).To(Succeed())
+			Expect(updated.Status.MatchedWorkloads).To(Equal([]string{"admin", "shop", "web"}))
+		})
+
+		It("should inject a regex matcher for the matched workloads", func() {
+			alertRule.Status.MatchedWorkloads = []string{"shop", "web.v2"}
+
+			rules := targetRules(alertRule)
+			Expect(rules).To(HaveLen(1))
+			Expect(rules[0].Expr).To(Equal(`kube_deployment_status_replicas_unavailable{deployment=~"shop|web\\.v2"} > 0`))
+		})
+
+		It("should expand the rules per matched workload", func() {
+			alertRule.Spec.WorkloadSelector.Mode = monitoringv1.WorkloadSelectorModeExpand
+			alertRule.Status.MatchedWorkloads = []string{"shop", "web"}
+
+			rules := targetRules(alertRule)
+			Expect(rules).To(HaveLen(2))
+			Expect(rules[0].Expr).To(Equal(`kube_deployment_status_replicas_unavailable{deployment="shop"} > 0`))
+			Expect(rules[0].Labels).To(HaveKeyWithValue("deployment", "shop"))
+			Expect(rules[1].Expr).To(Equal(`kube_deployment_status_replicas_unavailable{deployment="web"} > 0`))
+			Expect(rules[1].Labels).To(HaveKeyWithValue("deployment", "web"))
+		})
+
+		It("should emit no rules while no workload matches", func() {
+			Expect(targetRules(alertRule)).To(BeEmpty())
+		})
+	})
+
+	Context("When rendering template variables", func() {
+		var (
+			alertRule  *monitoringv1.AlertRule
+			fakeClient client.Client
+			reconciler *AlertRuleReconciler
+		)
+
+		BeforeEach(func() {
+			alertRule = &monitoringv1.AlertRule{
+				ObjectMeta: metav1.ObjectMeta{Name: "web-alert", Namespace: "default", Generation: 1},
+				Spec: monitoringv1.AlertRuleSpec{
+					Alert: "{{ .Workload.Name }}Down",
+					Expr:  `kube_deployment_status_replicas_available{namespace="{{ .Namespace }}", deployment="{{ .Workload.Name }}"} == 0`,
+					Labels: map[string]string{
+						"team": "{{ .Workload.Labels.team }}",
+					},
+					Annotations: map[string]string{
+						"summary": "{{ .Workload.Name }} has no available replicas on {{ $labels.instance }}",
+					},
+					WorkloadRef: &monitoringv1.WorkloadReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"},
+				},
+			}
+			fakeClient = fake.NewClientBuilder().
+				WithScheme(k8sClient.Scheme()).
+				WithRESTMapper(newTestRESTMapper(false)).
+				WithObjects(alertRule, newTestDeployment("web", map[string]string{"team": "payments"})).
+				WithStatusSubresource(alertRule).
+				Build()
+			reconciler = &AlertRuleReconciler{
+				Client:   fakeClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(10),
+			}
+		})
+
+		It("should resolve the variables from the referenced workload and namespace", func() {
+			data, err := reconciler.templateData(ctx, alertRule)
+			Expect(err).NotTo(HaveOccurred())
+
+			rendered, err := renderAlertRule(alertRule, data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rendered.Spec.Alert).To(Equal("webDown"))
+			Expect(rendered.Spec.Expr).To(Equal(`kube_deployment_status_replicas_available{namespace="default", deployment="web"} == 0`))
+			Expect(rendered.Spec.Labels).To(HaveKeyWithValue("team", "payments"))
+			Expect(rendered.Spec.Annotations).To(HaveKeyWithValue("summary", "web has no available replicas on {{ $labels.instance }}"))
+
+			// 원본 AlertRule은 변경하지 않음
+			Expect(alertRule.Spec.Alert).To(Equal("{{ .Workload.Name }}Down"))
+		})
+
+		It("should leave every Prometheus template action as it is", func() {
+			alertRule.Spec.Annotations = map[string]string{
+				"summary":     "{{ .Workload.Name }} on {{ .Labels.instance }} is at {{ .Value | humanize }}",
+				"description": `{{ with query "up{job='web'}" }}{{ . | first | value }}{{ end }} for {{ $value | humanizeDuration }}`,
+				"runbook":     "{{- .Workload.Labels.team -}} / web",
+			}
+			data, err := reconciler.templateData(ctx, alertRule)
+			Expect(err).NotTo(HaveOccurred())
+
+			rendered, err := renderAlertRule(alertRule, data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rendered.Spec.Annotations).To(Equal(map[string]string{
+				"summary":     "web on {{ .Labels.instance }} is at {{ .Value | humanize }}",
+				"description": `{{ with query "up{job='web'}" }}{{ . | first | value }}{{ end }} for {{ $value | humanizeDuration }}`,
+				"runbook":     "payments/ web",
+			}))
+
+			By("not rendering AlertRules without variables")
+			alertRule.Spec.Alert = "WebDown"
+			alertRule.Spec.Expr = `kube_deployment_status_replicas_available{deployment="web"} == 0`
+			alertRule.Spec.Labels = nil
+			alertRule.Spec.Annotations = map[string]string{"summary": "{{ .Labels.instance }} is at {{ .Value | humanize }}"}
+			Expect(hasTemplates(&alertRule.Spec)).To(BeFalse())
+		})
+
+		It("should render every action referencing a variable", func() {
+			alertRule.Spec.Expr = `up{namespace="{{ printf "%s" .Namespace }}"{{ if .Workload }}, job="{{ .Workload.Name }}"{{ end }}} == 0`
+			alertRule.Spec.Annotations = map[string]string{
+				"summary": `{{ with .Workload }}{{ .Kind }} {{ .Name }}{{ else }}{{ $.Name }}{{ end }} is down on {{ $labels.instance }}`,
+				"details": `{{ range $labels }}{{ .Name }}{{ end }}`,
+			}
+			data, err := reconciler.templateData(ctx, alertRule)
+			Expect(err).NotTo(HaveOccurred())
+
+			rendered, err := renderAlertRule(alertRule, data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rendered.Spec.Expr).To(Equal(`up{namespace="default", job="web"} == 0`))
+			Expect(rendered.Spec.Annotations).To(Equal(map[string]string{
+				"summary": "Deployment web is down on {{ $labels.instance }}",
+				"details": `{{ range $labels }}{{ .Name }}{{ end }}`,
+			}))
+
+			By("rendering the else branch without a workload")
+			alertRule.Spec.Alert = "WebDown"
+			alertRule.Spec.Labels = nil
+			data.Workload = nil
+			rendered, err = renderAlertRule(alertRule, data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rendered.Spec.Expr).To(Equal(`up{namespace="default"} == 0`))
+			Expect(rendered.Spec.Annotations).To(HaveKeyWithValue("summary", "web-alert is down on {{ $labels.instance }}"))
+		})
+
+		It("should report a missing variable in a RenderFailed condition", func() {
+			alertRule.Spec.Labels["owner"] = "{{ .Workload.Labels.owner }}"
+			Expect(fakeClient.Update(ctx, alertRule)).To(Succeed())
+
+			key := client.ObjectKeyFromObject(alertRule)
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			updated := &monitoringv1.AlertRule{}
+			Expect(fakeClient.Get(ctx, key, updated)).To(Succeed())
+			renderFailed := meta.FindStatusCondition(updated.Status.Conditions, "RenderFailed")
+			Expect(renderFailed).NotTo(BeNil())
+			Expect(renderFailed.Status).To(Equal(metav1.ConditionTrue))
+			Expect(renderFailed.Message).To(ContainSubstring("owner"))
+			ready := meta.FindStatusCondition(updated.Status.Conditions, "Ready")
+			Expect(ready).NotTo(BeNil())
+			Expect(ready.Reason).To(Equal("RenderFailed"))
+
+			recording, alerting := reconciler.buildRuleSets(ctx, updated)
+			Expect(recording).To(BeEmpty())
+			Expect(alerting).To(BeEmpty())
+		})
+
+		It("should re-render the AlertRules referencing a changed workload", func() {
+			Expect(reconciler.alertRulesSelecting("Deployment")(ctx, newTestDeployment("web", nil))).To(ConsistOf(
+				reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "web-alert"}},
+			))
+			Expect(reconciler.alertRulesSelecting("Deployment")(ctx, newTestDeployment("db", nil))).To(BeEmpty())
+		})
+	})
+
+	Context("When enforcing namespace isolation", func() {
+		It("should inject the namespace matcher into every vector selector", func() {
+			rule, err := enforceNamespace(monitoringv1.Rule{
+				Alert: "HighErrorRate",
+				Expr:  `sum(rate(http_requests_total{code=~"5..", namespace="other"}[5m])) / sum(rate(http_requests_total[5m])) > 0.1`,
+			}, "team-a")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rule.Expr).To(Equal(`sum(rate(http_requests_total{code=~"5..",namespace="team-a"}[5m])) / ` +
+				`sum(rate(http_requests_total{namespace="team-a"}[5m])) > 0.1`))
+		})
+
+		It("should follow the flag unless the Namespace overrides it", func() {
+			alertRule := &monitoringv1.AlertRule{
+				ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "team-a"},
+				Spec: monitoringv1.AlertRuleSpec{
+					Alert: "ApiDown",
+					Expr:  `up{job="api"} == 0`,
+				},
+			}
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(k8sClient.Scheme()).
+				WithObjects(alertRule, newNamespace("team-a", ""), newNamespace("team-b", monitoringv1.TenantIsolationDisabled)).
+				Build()
+			reconciler := &AlertRuleReconciler{Client: fakeClient, Scheme: k8sClient.Scheme(), EnforceNamespaceMatcher: true}
+
+			_, alerting := reconciler.buildRuleSets(ctx, alertRule)
+			Expect(alerting).To(HaveLen(1))
+			Expect(alerting[0].(map[string]interface{})["expr"]).To(Equal(`up{job="api",namespace="team-a"} == 0`))
+
+			alertRule.Namespace = "team-b"
+			_, alerting = reconciler.buildRuleSets(ctx, alertRule)
+			Expect(alerting[0].(map[string]interface{})["expr"]).To(Equal(`up{job="api"} == 0`))
+
+			reconciler.EnforceNamespaceMatcher = false
+			Expect(fakeClient.Create(ctx, newNamespace("team-c", monitoringv1.TenantIsolationEnforced))).To(Succeed())
+			alertRule.Namespace = "team-c"
+			_, alerting = reconciler.buildRuleSets(ctx, alertRule)
+			Expect(alerting[0].(map[string]interface{})["expr"]).To(Equal(`up{job="api",namespace="team-c"} == 0`))
+
+			Expect(reconciler.alertRulesInNamespace(ctx, newNamespace("team-a", ""))).To(ConsistOf(
+				reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "api"}},
+			))
+		})
+		It("should not report Ready when a rule cannot be restricted to the namespace", func() {
+			alertRule := &monitoringv1.AlertRule{
+				ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "team-a", Generation: 1},
+				Spec: monitoringv1.AlertRuleSpec{
+					Rules: []monitoringv1.Rule{
+						{Alert: "ApiDown", Expr: `up{job="api"} == 0`},
+						{Alert: "ApiErrors", Expr: `rate(http_requests_total{code=~"5.."}[5m] > 1`},
+					},
+				},
+			}
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(k8sClient.Scheme()).
+				WithRESTMapper(newTestRESTMapper(true)).
+				WithObjects(alertRule, newNamespace("team-a", monitoringv1.TenantIsolationEnforced)).
+				WithStatusSubresource(alertRule).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Apply: func(ctx context.Context, c client.WithWatch, obj runtime.ApplyConfiguration, opts ...client.ApplyOption) error {
+						Fail("rules must not be published")
+						return nil
+					},
+				}).
+				Build()
+			reconciler := &AlertRuleReconciler{Client: fakeClient, Scheme: k8sClient.Scheme(), Recorder: record.NewFakeRecorder(10)}
+
+			key := client.ObjectKeyFromObject(alertRule)
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			updated := &monitoringv1.AlertRule{}
+			Expect(fakeClient.Get(ctx, key, updated)).To(Succeed())
+			ready := meta.FindStatusCondition(updated.Status.Conditions, "Ready")
+			Expect(ready).NotTo(BeNil())
+			Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+			Expect(ready.Reason).To(Equal("IsolationFailed"))
+			Expect(ready.Message).To(ContainSubstring("rule ApiErrors"))
+		})
+	})
+
+	Context("When running rule tests", func() {
+		var (
+			alertRule  *monitoringv1.AlertRule
+			fakeClient client.Client
+			reconciler *AlertRuleReconciler
+		)
+
+		BeforeEach(func() {
+			alertRule = &monitoringv1.AlertRule{
+				ObjectMeta: metav1.ObjectMeta{Name: "api-alert", Namespace: "default", Generation: 1},
+				Spec: monitoringv1.AlertRuleSpec{
+					Rules: []monitoringv1.Rule{
+						{Record: "job:up:sum", Expr: "sum by (job) (up)"},
+						{
+							Alert:       "ApiDown",
+							Expr:        `job:up:sum{job="api"} == 0`,
+							For:         "5m",
+							Labels:      map[string]string{"severity": "critical"},
+							Annotations: map[string]string{"summary": "{{ $labels.job }} is down, value {{ $value }}"},
+						},
+					},
+					Tests: []monitoringv1.RuleTest{{
+						Name:     "api goes down",
+						Interval: "1m",
+						InputSeries: []monitoringv1.TestSeries{
+							{Series: `up{job="api", instance="api-0"}`, Values: "1 1 0x10"},
+						},
+						AlertRuleTests: []monitoringv1.AlertRuleTest{
+							// 2m부터 0이지만 for 5m 동안은 pending
+							{EvalTime: "4m", Alertname: "ApiDown"},
+							{EvalTime: "8m", Alertname: "ApiDown", ExpAlerts: []monitoringv1.ExpectedAlert{{
+								ExpLabels:      map[string]string{"job": "api", "severity": "critical"},
+								ExpAnnotations: map[string]string{"summary": "api is down, value 0"},
+							}}},
+						},
+					}},
+				},
+			}
+			fakeClient = fake.NewClientBuilder().
+				WithScheme(k8sClient.Scheme()).
+				WithRESTMapper(newTestRESTMapper(false)).
+				WithObjects(alertRule).
+				WithStatusSubresource(alertRule).
+				Build()
+			reconciler = &AlertRuleReconciler{
+				Client:   fakeClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(10),
+			}
+		})
+
+		It("should evaluate recording rules, for durations and annotation templates", func() {
+			failures, err := reconciler.runRuleTests(ctx, alertRule)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(failures).To(BeEmpty())
+		})
+
+		It("should reuse the results until the generation changes", func() {
+			failures, err := reconciler.runRuleTests(ctx, alertRule)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(failures).To(BeEmpty())
+
+			alertRule.Spec.Tests[0].AlertRuleTests[1].ExpAlerts = nil
+			failures, err = reconciler.runRuleTests(ctx, alertRule)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(failures).To(BeEmpty())
+
+			alertRule.Generation++
+			failures, err = reconciler.runRuleTests(ctx, alertRule)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(failures).To(HaveLen(1))
+		})
+
+		It("should refuse eval times beyond the evaluation budget", func() {
+			alertRule.Spec.Tests[0].AlertRuleTests[1].EvalTime = "1d"
+
+			_, err := reconciler.runRuleTests(ctx, alertRule)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("exceeds 6h"))
+		})
+
+		It("should keep firing alerts for keep_firing_for and record their ALERTS series", func() {
+			rules := []testRule{
+				{alert: "ApiDown", expr: `up{job="api"} == 0`, hold: time.Minute, keepFiringFor: 3 * time.Minute},
+				{alert: "ApiDownFiring", expr: `ALERTS{alertname="ApiDown", alertstate="firing"}`},
+			}
+			test := &monitoringv1.RuleTest{
+				InputSeries: []monitoringv1.TestSeries{{Series: `up{job="api"}`, Values: "0 0 0 1x10"}},
+				AlertRuleTests: []monitoringv1.AlertRuleTest{
+					{EvalTime: "0m", Alertname: "ApiDownFiring"},
+					// 3m부터 조건을 만족하지 않지만 keep_firing_for 3m 동안은 firing
+					{EvalTime: "5m", Alertname: "ApiDown", ExpAlerts: []monitoringv1.ExpectedAlert{{
+						ExpLabels: map[string]string{"job": "api"},
+					}}},
+					{EvalTime: "5m", Alertname: "ApiDownFiring", ExpAlerts: []monitoringv1.ExpectedAlert{{
+						ExpLabels: map[string]string{"job": "api", "alertstate": "firing"},
+					}}},
+					{EvalTime: "6m", Alertname: "ApiDown"},
+				},
+			}
+
+			failures, err := runRuleTest(ctx, rules, test, testGroup{interval: time.Minute})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(failures).To(BeEmpty())
+		})
+
+		It("should evaluate the rules at the query offset of the group", func() {
+			alertRule.Spec.GroupSettings = &monitoringv1.RuleGroupSettings{QueryOffset: "3m"}
+
+			// 8m에는 5m의 데이터를 조회하므로 alert는 아직 pending
+			failures, err := reconciler.runRuleTests(ctx, alertRule)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(failures).To(HaveLen(1))
+			Expect(failures[0]).To(ContainSubstring("alertname ApiDown at 8m"))
+		})
+
+		It("should report alerts that do not match the expectations", func() {
+			alertRule.Spec.Tests[0].AlertRuleTests[0].ExpAlerts = []monitoringv1.ExpectedAlert{{
+				ExpLabels: map[string]string{"job": "api", "severity": "critical"},
+			}}
+
+			failures, err := reconciler.runRuleTests(ctx, alertRule)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(failures).To(HaveLen(1))
+			Expect(failures[0]).To(ContainSubstring("api goes down: alertname ApiDown at 4m"))
+		})
+
+		It("should not publish the rules of an AlertRule whose tests fail", func() {
+			alertRule.Spec.Tests[0].AlertRuleTests[1].ExpAlerts[0].ExpLabels["severity"] = "warning"
+			Expect(fakeClient.Update(ctx, alertRule)).To(Succeed())
+
+			key := client.ObjectKeyFromObject(alertRule)
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+			Expect(err).NotTo(HaveOccurred())
+
+			updated := &monitoringv1.AlertRule{}
+			Expect(fakeClient.Get(ctx, key, updated)).To(Succeed())
+			testsPassed := meta.FindStatusCondition(updated.Status.Conditions, "TestsPassed")
+			Expect(testsPassed).NotTo(BeNil())
+			Expect(testsPassed.Status).To(Equal(metav1.ConditionFalse))
+			Expect(testsPassed.Message).To(ContainSubstring("ApiDown at 8m"))
+			ready := meta.FindStatusCondition(updated.Status.Conditions, "Ready")
+			Expect(ready).NotTo(BeNil())
+			Expect(ready.Reason).To(Equal("TestsFailed"))
+
+			// 공유 rule group에서도 제외
+			groups, _, err := reconciler.buildRuleGroups(ctx, []monitoringv1.AlertRule{*updated})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(groups).To(BeEmpty())
+		})
+	})
+
+	Context("When routing alerts to a receiver", func() {
+		var (
+			alertRule *monitoringv1.AlertRule
+			applied   []*unstructured.Unstructured
+		)
+
+		BeforeEach(func() {
+			alertRule = &monitoringv1.AlertRule{
+				ObjectMeta: metav1.ObjectMeta{Name: "payments", Namespace: "team-payments", Generation: 2, UID: "payments-uid"},
+				Spec: monitoringv1.AlertRuleSpec{
+					Severity: "critical",
+					Rules: []monitoringv1.Rule{
+						{Record: "job:payments:errors", Expr: `sum by (job) (rate(payment_errors_total[5m]))`},
+						{Alert: "PaymentsDown", Expr: `up{job="payments"} == 0`},
+						{Alert: "PaymentErrors", Expr: `job:payments:errors > 0.1`},
+					},
+					Routing: &monitoringv1.Routing{
+						Receiver:       "payments-oncall",
+						ReceiverConfig: &runtime.RawExtension{Raw: []byte(`{"webhookConfigs":[{"url":"http://oncall.example.com/hook"}]}`)},
+						GroupBy:        []string{"alertname", "job"},
+						RepeatInterval: "4h",
+						Matchers:       []monitoringv1.RouteMatcher{{Name: "severity", Value: "critical"}},
+					},
+				},
+			}
+			applied = nil
+		})
+
+		newRoutingClient := func(withCRD bool, objs ...client.Object) client.Client {
+			mapper := meta.NewDefaultRESTMapper(nil)
+			mapper.Add(monitoringv1.GroupVersion.WithKind("AlertRule"), meta.RESTScopeNamespace)
+			if withCRD {
+				mapper.Add(alertmanagerConfigGVK(), meta.RESTScopeNamespace)
+			}
+			return fake.NewClientBuilder().
+				WithScheme(k8sClient.Scheme()).
+				WithRESTMapper(mapper).
+				WithObjects(objs...).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Apply: func(ctx context.Context, c client.WithWatch, obj runtime.ApplyConfiguration, opts ...client.ApplyOption) error {
+						data, err := json.Marshal(obj)
+						if err != nil {
+							return err
+						}
+						u := &unstructured.Unstructured{}
+						if err := u.UnmarshalJSON(data); err != nil {
+							return err
+						}
+						applied = append(applied, u)
+						return nil
+					},
+				}).
+				Build()
+		}
+
+		It("should route the alerts of the AlertRule to its receiver", func() {
+			reconciler := &AlertRuleReconciler{Client: newRoutingClient(true), Scheme: k8sClient.Scheme()}
+
+			config, err := reconciler.buildAlertmanagerConfig(ctx, alertRule)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(config.GetName()).To(Equal("payments"))
+			Expect(config.GetNamespace()).To(Equal("team-payments"))
+			Expect(metav1.IsControlledBy(config, alertRule)).To(BeTrue())
+
+			route, _, _ := unstructured.NestedMap(config.Object, "spec", "route")
+			Expect(route).To(HaveKeyWithValue("receiver", "payments-oncall"))
+			Expect(route).To(HaveKeyWithValue("repeatInterval", "4h"))
+			Expect(route).To(HaveKeyWithValue("groupBy", []interface{}{"alertname", "job"}))
+			Expect(route["matchers"]).To(Equal([]interface{}{
+				map[string]interface{}{"name": "alertname", "value": "PaymentErrors|PaymentsDown", "matchType": "=~"},
+				map[string]interface{}{"name": "severity", "value": "critical", "matchType": "="},
+			}))
+
+			receivers, _, _ := unstructured.NestedSlice(config.Object, "spec", "receivers")
+			Expect(receivers).To(Equal([]interface{}{map[string]interface{}{
+				"name":           "payments-oncall",
+				"webhookConfigs": []interface{}{map[string]interface{}{"url": "http://oncall.example.com/hook"}},
+			}}))
+		})
+
+		It("should apply the AlertmanagerConfig and report it in the RoutingReady condition", func() {
+			reconciler := &AlertRuleReconciler{Client: newRoutingClient(false), Scheme: k8sClient.Scheme()}
+			Expect(reconciler.reconcileRouting(ctx, alertRule)).To(Succeed())
+			routingReady := meta.FindStatusCondition(alertRule.Status.Conditions, "RoutingReady")
+			Expect(routingReady).NotTo(BeNil())
+			Expect(routingReady.Status).To(Equal(metav1.ConditionFalse))
+			Expect(routingReady.Reason).To(Equal("CRDNotInstalled"))
+			Expect(applied).To(BeEmpty())
+
+			reconciler.Client = newRoutingClient(true)
+			Expect(reconciler.reconcileRouting(ctx, alertRule)).To(Succeed())
+			routingReady = meta.FindStatusCondition(alertRule.Status.Conditions, "RoutingReady")
+			Expect(routingReady.Status).To(Equal(metav1.ConditionTrue))
+			Expect(applied).To(HaveLen(1))
+			Expect(applied[0].GetKind()).To(Equal("AlertmanagerConfig"))
+		})
+
+		It("should delete the AlertmanagerConfig once the routing is removed", func() {
+			builder := &AlertRuleReconciler{Client: newRoutingClient(true), Scheme: k8sClient.Scheme()}
+			owned, err := builder.buildAlertmanagerConfig(ctx, alertRule)
+			Expect(err).NotTo(HaveOccurred())
+			foreign := owned.DeepCopy()
+			foreign.SetName("shared")
+			foreign.SetOwnerReferences(nil)
+
+			fakeClient := newRoutingClient(true, owned, foreign)
+			reconciler := &AlertRuleReconciler{Client: fakeClient, Scheme: k8sClient.Scheme()}
+
+			alertRule.Spec.Routing = nil
+			Expect(reconciler.reconcileRouting(ctx, alertRule)).To(Succeed())
+			Expect(meta.FindStatusCondition(alertRule.Status.Conditions, "RoutingReady")).To(BeNil())
+
+			remaining := &unstructured.UnstructuredList{}
+			remaining.SetGroupVersionKind(alertmanagerConfigGVK().GroupVersion().WithKind("AlertmanagerConfigList"))
+			Expect(fakeClient.List(ctx, remaining, client.InNamespace("team-payments"))).To(Succeed())
+			Expect(remaining.Items).To(HaveLen(1))
+			Expect(remaining.Items[0].GetName()).To(Equal("shared"))
+		})
+	})
 })
+
+// watchRecorder is a controller recording the sources it is asked to watch
+type watchRecorder struct {
+	controller.Controller
+	sources []source.Source
+}
+
+func (w *watchRecorder) Watch(src source.Source) error {
+	w.sources = append(w.sources, src)
+	return nil
+}
+
+// newApplyConflict returns the error of a server-side apply conflicting with the fields of another field manager
+func newApplyConflict(name, manager string) error {
+	return &errors.StatusError{ErrStatus: metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    409,
+		Reason:  metav1.StatusReasonConflict,
+		Message: fmt.Sprintf("Apply failed with 1 conflict: conflict with %q: .spec.groups", manager),
+		Details: &metav1.StatusDetails{
+			Name:  name,
+			Group: "monitoring.coreos.com",
+			Kind:  "prometheusrules",
+			Causes: []metav1.StatusCause{{
+				Type:    metav1.CauseTypeFieldManagerConflict,
+				Message: fmt.Sprintf("conflict with %q", manager),
+				Field:   ".spec.groups",
+			}},
+		},
+	}}
+}
+
+// newNamespace returns a Namespace with the given tenant isolation annotation
+func newNamespace(name, isolation string) *corev1.Namespace {
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	if isolation != "" {
+		namespace.Annotations = map[string]string{monitoringv1.TenantIsolationAnnotation: isolation}
+	}
+	return namespace
+}
+
+// newTestRESTMapper returns a RESTMapper knowing AlertRules, ClusterAlertRules and, optionally, PrometheusRules
+func newTestRESTMapper(withPrometheusRule bool) meta.RESTMapper {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(monitoringv1.GroupVersion.WithKind("AlertRule"), meta.RESTScopeNamespace)
+	mapper.Add(monitoringv1.GroupVersion.WithKind("ClusterAlertRule"), meta.RESTScopeRoot)
+	if withPrometheusRule {
+		mapper.Add(prometheusRuleGVK(), meta.RESTScopeNamespace)
+	}
+
+	return mapper
+}
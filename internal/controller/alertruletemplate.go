@@ -0,0 +1,163 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	monitoringv1 "github.com/Kim-Yukyung/k8s-alert-rule-operator/api/v1"
+)
+
+// templateSelects reports whether an AlertRuleTemplate applies to an object
+// with the given labels living in the given namespace
+func templateSelects(tmpl *monitoringv1.AlertRuleTemplate, objLabels map[string]string, namespace *corev1.Namespace) (bool, error) {
+	matches, err := selectorMatches(tmpl.Spec.Selector, objLabels)
+	if err != nil || !matches {
+		return false, err
+	}
+
+	return selectorMatches(tmpl.Spec.NamespaceSelector, namespace.Labels)
+}
+
+// selectorMatches reports whether a label selector matches a label set.
+// A nil selector matches everything.
+func selectorMatches(selector *metav1.LabelSelector, set map[string]string) (bool, error) {
+	if selector == nil {
+		return true, nil
+	}
+
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false, fmt.Errorf("invalid label selector: %w", err)
+	}
+
+	return s.Matches(labels.Set(set)), nil
+}
+
+// renderTemplateRules renders the rules of an AlertRuleTemplate against data
+func renderTemplateRules(tmpl *monitoringv1.AlertRuleTemplate, data interface{}) ([]monitoringv1.Rule, error) {
+	render := func(name, text string) (string, error) {
+		return renderString(name, text, data)
+	}
+
+	rules := make([]monitoringv1.Rule, 0, len(tmpl.Spec.Rules))
+	for i, rule := range tmpl.Spec.Rules {
+		rendered, err := renderRule(rule, render)
+		if err != nil {
+			return nil, fmt.Errorf("rules[%d]: %w", i, err)
+		}
+
+		// severity label이 없는 alerting rule에는 template의 severity를 적용
+		if rendered.Alert != "" && tmpl.Spec.Severity != "" {
+			if _, ok := rendered.Labels["severity"]; !ok {
+				if rendered.Labels == nil {
+					rendered.Labels = map[string]string{}
+				}
+				rendered.Labels["severity"] = tmpl.Spec.Severity
+			}
+		}
+		rules = append(rules, rendered)
+	}
+
+	return rules, nil
+}
+
+// renderFunc renders a single templated field value
+type renderFunc func(name, text string) (string, error)
+
+// renderRule renders every templated field of a rule
+func renderRule(rule monitoringv1.Rule, render renderFunc) (monitoringv1.Rule, error) {
+	var err error
+	rendered := monitoringv1.Rule{}
+
+	if rendered.Alert, err = render("alert", rule.Alert); err != nil {
+		return rendered, err
+	}
+	if rendered.Record, err = render("record", rule.Record); err != nil {
+		return rendered, err
+	}
+	if rendered.Expr, err = render("expr", rule.Expr); err != nil {
+		return rendered, err
+	}
+	if rendered.For, err = render("for", rule.For); err != nil {
+		return rendered, err
+	}
+	if rendered.Labels, err = renderStringMap("labels", rule.Labels, render); err != nil {
+		return rendered, err
+	}
+	if rendered.Annotations, err = renderStringMap("annotations", rule.Annotations, render); err != nil {
+		return rendered, err
+	}
+
+	return rendered, nil
+}
+
+// renderStringMap renders the values of a map of templates
+func renderStringMap(name string, values map[string]string, render renderFunc) (map[string]string, error) {
+	if values == nil {
+		return nil, nil
+	}
+
+	rendered := make(map[string]string, len(values))
+	for k, v := range values {
+		out, err := render(fmt.Sprintf("%s[%s]", name, k), v)
+		if err != nil {
+			return nil, err
+		}
+		rendered[k] = out
+	}
+
+	return rendered, nil
+}
+
+// prometheusAction matches the template actions of Prometheus alert annotations and labels,
+// such as {{ $labels.pod }} or {{ $value | humanize }}, which are evaluated by Prometheus
+var prometheusAction = regexp.MustCompile(`\{\{[^{}]*\$(labels|value|externalLabels|externalURL)\b[^{}]*\}\}`)
+
+// renderString executes a single Go template. Missing keys are reported as errors
+// instead of silently rendering "<no value>". Prometheus template actions are kept as they are.
+func renderString(name, text string, data interface{}) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	// Prometheus 템플릿은 문자열 상수로 감싸서 그대로 출력
+	text = prometheusAction.ReplaceAllStringFunc(text, func(action string) string {
+		return "{{" + strconv.Quote(action) + "}}"
+	})
+
+	t, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse %s template: %w", name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("unable to render %s template: %w", name, err)
+	}
+
+	return buf.String(), nil
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	monitoringv1 "github.com/Kim-Yukyung/k8s-alert-rule-operator/api/v1"
+)
+
+// maxReportedRenderFailures limits the render failures listed in the Ready condition of an AlertRuleTemplate
+const maxReportedRenderFailures = 3
+
+// AlertRuleTemplateReconciler reports in the status of an AlertRuleTemplate whether it renders
+// for every workload it selects
+type AlertRuleTemplateReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=monitoring.example.com,resources=alertruletemplates,verbs=get;list;watch
+// +kubebuilder:rbac:groups=monitoring.example.com,resources=alertruletemplates/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets;daemonsets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=batch,resources=jobs;cronjobs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+
+// Reconcile renders an AlertRuleTemplate for every workload it selects and records the result
+// in its Ready condition.
+func (r *AlertRuleTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logf.FromContext(ctx)
+
+	tmpl := &monitoringv1.AlertRuleTemplate{}
+	if err := r.Get(ctx, req.NamespacedName, tmpl); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "unable to fetch AlertRuleTemplate")
+		return ctrl.Result{}, err
+	}
+
+	condition := metav1.Condition{
+		Type:               "Ready",
+		Status:             metav1.ConditionTrue,
+		Reason:             "Rendered",
+		ObservedGeneration: tmpl.Generation,
+	}
+
+	// 잘못된 selector는 어떤 workload도 선택하지 못하므로 렌더링하기 전에 기록
+	var rendered int
+	var failures []string
+	err := validateTemplateSelectors(tmpl)
+	if err == nil {
+		rendered, failures, err = r.renderForWorkloads(ctx, tmpl)
+		if err != nil {
+			logger.Error(err, "unable to render AlertRuleTemplate")
+			return ctrl.Result{}, err
+		}
+	}
+
+	switch {
+	case err != nil:
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "InvalidSelector"
+		condition.Message = err.Error()
+	case len(failures) == 0:
+		condition.Message = fmt.Sprintf("The template renders for all %d selected workloads", rendered)
+	default:
+		reported := failures
+		if len(reported) > maxReportedRenderFailures {
+			reported = append(reported[:maxReportedRenderFailures:maxReportedRenderFailures],
+				fmt.Sprintf("and %d more", len(failures)-maxReportedRenderFailures))
+		}
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "RenderFailed"
+		condition.Message = fmt.Sprintf("The template cannot be rendered for %d of %d selected workloads: %s",
+			len(failures), rendered+len(failures), strings.Join(reported, "; "))
+		logger.Info("AlertRuleTemplate cannot be rendered for some workloads", "failures", len(failures))
+	}
+
+	original := tmpl.Status.DeepCopy()
+	meta.SetStatusCondition(&tmpl.Status.Conditions, condition)
+	if equality.Semantic.DeepEqual(original, &tmpl.Status) {
+		return ctrl.Result{}, nil
+	}
+
+	if err := r.Status().Update(ctx, tmpl); err != nil {
+		logger.Error(err, "unable to update AlertRuleTemplate status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// validateTemplateSelectors returns an error when a selector of an AlertRuleTemplate is invalid
+func validateTemplateSelectors(tmpl *monitoringv1.AlertRuleTemplate) error {
+	if _, err := selectorMatches(tmpl.Spec.Selector, nil); err != nil {
+		return fmt.Errorf("selector: %w", err)
+	}
+	if _, err := selectorMatches(tmpl.Spec.NamespaceSelector, nil); err != nil {
+		return fmt.Errorf("namespaceSelector: %w", err)
+	}
+
+	return nil
+}
+
+// renderForWorkloads renders a template for every workload it selects, returning how many
+// renders succeeded and a description of each one that failed
+func (r *AlertRuleTemplateReconciler) renderForWorkloads(ctx context.Context, tmpl *monitoringv1.AlertRuleTemplate) (int, []string, error) {
+	namespaceList := &corev1.NamespaceList{}
+	if err := r.List(ctx, namespaceList); err != nil {
+		return 0, nil, fmt.Errorf("unable to list Namespaces: %w", err)
+	}
+	namespaces := make(map[string]*corev1.Namespace, len(namespaceList.Items))
+	for i := range namespaceList.Items {
+		namespaces[namespaceList.Items[i].Name] = &namespaceList.Items[i]
+	}
+
+	rendered := 0
+	var failures []string
+	for _, kindName := range SupportedWorkloadKinds {
+		if !templateAppliesToKind(tmpl, kindName) {
+			continue
+		}
+		kind := workloadKinds[kindName]
+
+		list := kind.newList()
+		if err := r.List(ctx, list); err != nil {
+			return 0, nil, fmt.Errorf("unable to list %ss: %w", kindName, err)
+		}
+		workloads, err := extractObjects(list)
+		if err != nil {
+			return 0, nil, err
+		}
+
+		for _, workload := range workloads {
+			namespace, ok := namespaces[workload.GetNamespace()]
+			if !ok || (kind.ignore != nil && kind.ignore(workload)) {
+				continue
+			}
+
+			matches, err := templateSelects(tmpl, workload.GetLabels(), namespace)
+			if err != nil {
+				return 0, nil, err
+			}
+			if !matches {
+				continue
+			}
+
+			if _, err := renderTemplateRules(tmpl, workload); err != nil {
+				failures = append(failures, fmt.Sprintf("%s %s/%s: %v", kindName, workload.GetNamespace(), workload.GetName(), err))
+				continue
+			}
+			rendered++
+		}
+	}
+
+	return rendered, failures, nil
+}
+
+// templatesForKind returns a map function enqueuing every AlertRuleTemplate applying to a workload kind
+func (r *AlertRuleTemplateReconciler) templatesForKind(kind string) func(context.Context, client.Object) []reconcile.Request {
+	return func(ctx context.Context, _ client.Object) []reconcile.Request {
+		return r.templates(ctx, func(tmpl *monitoringv1.AlertRuleTemplate) bool {
+			return templateAppliesToKind(tmpl, kind)
+		})
+	}
+}
+
+// templates returns reconcile requests for the AlertRuleTemplates accepted by filter
+func (r *AlertRuleTemplateReconciler) templates(ctx context.Context, filter func(*monitoringv1.AlertRuleTemplate) bool) []reconcile.Request {
+	templates := &monitoringv1.AlertRuleTemplateList{}
+	if err := r.List(ctx, templates); err != nil {
+		logf.FromContext(ctx).Error(err, "unable to list AlertRuleTemplates")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range templates.Items {
+		if !filter(&templates.Items[i]) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: templates.Items[i].Name},
+		})
+	}
+
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *AlertRuleTemplateReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&monitoringv1.AlertRuleTemplate{}, builder.WithPredicates(predicate.GenerationChangedPredicate{}))
+
+	// 템플릿이 참조하는 workload의 label, annotation이 바뀌면 렌더링 결과도 바뀔 수 있음
+	for _, kind := range SupportedWorkloadKinds {
+		b = b.Watches(workloadKinds[kind].newObject(),
+			handler.EnqueueRequestsFromMapFunc(r.templatesForKind(kind)),
+			builder.WithPredicates(predicate.Or(predicate.LabelChangedPredicate{}, predicate.AnnotationChangedPredicate{})))
+	}
+
+	// Namespace label이 바뀌면 NamespaceSelector가 선택하는 workload가 바뀜
+	b = b.Watches(&corev1.Namespace{},
+		handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, _ client.Object) []reconcile.Request {
+			return r.templates(ctx, func(tmpl *monitoringv1.AlertRuleTemplate) bool {
+				return tmpl.Spec.NamespaceSelector != nil
+			})
+		}),
+		builder.WithPredicates(predicate.LabelChangedPredicate{}))
+
+	return b.Named("alertruletemplate").Complete(r)
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	monitoringv1 "github.com/Kim-Yukyung/k8s-alert-rule-operator/api/v1"
+)
+
+// RuleBackend publishes the rules generated from AlertRules to a rule evaluator
+type RuleBackend interface {
+	// Name returns the backend name as used in the AlertRule spec
+	Name() string
+
+	// Available returns a message explaining why the backend cannot accept rules,
+	// or an empty string when it can
+	Available(ctx context.Context) (string, error)
+
+	// Publish creates or updates the rules of an AlertRule
+	Publish(ctx context.Context, alertRule *monitoringv1.AlertRule) (publishResult, error)
+
+	// Delete removes the rules an AlertRule published in the given rule group, once the
+	// AlertRule is being deleted or has moved to another backend or rule group
+	Delete(ctx context.Context, alertRule *monitoringv1.AlertRule, group string) error
+
+	// Reference returns where the rules of an AlertRule are published, or nil when they are not
+	Reference(ctx context.Context, alertRule *monitoringv1.AlertRule) (*monitoringv1.GeneratedReference, error)
+}
+
+// publishResult describes the rules a backend published for an AlertRule
+type publishResult struct {
+	// drift describes a manual change of the published rules that had to be reverted
+	drift string
+	// ruleHash is a content hash of the rules published for the AlertRule
+	ruleHash string
+}
+
+// ruleHashes maps the names of the AlertRules of a namespace to a content hash of the rules built for them
+type ruleHashes map[string]string
+
+// backendFor returns the backend an AlertRule is published to
+func (r *AlertRuleReconciler) backendFor(alertRule *monitoringv1.AlertRule) RuleBackend {
+	name := alertRule.Spec.Backend
+	if name == "" {
+		name = r.DefaultBackend
+	}
+
+	switch name {
+	case monitoringv1.BackendRuler:
+		return &rulerBackend{r: r, config: r.Ruler}
+	case monitoringv1.BackendConfigMap:
+		return &configMapBackend{r: r, config: r.ConfigMap}
+	default:
+		return &prometheusRuleBackend{r: r}
+	}
+}
+
+// backends returns every configured backend rules of an AlertRule may have been published to
+func (r *AlertRuleReconciler) backends() []RuleBackend {
+	backends := []RuleBackend{&prometheusRuleBackend{r: r}}
+	if r.Ruler.URL != "" {
+		backends = append(backends, &rulerBackend{r: r, config: r.Ruler})
+	}
+	if r.ConfigMap.Name != "" && r.ConfigMap.Namespace != "" {
+		backends = append(backends, &configMapBackend{r: r, config: r.ConfigMap})
+	}
+
+	return backends
+}
+
+// prometheusRuleBackend publishes rules as prometheus-operator PrometheusRule objects
+type prometheusRuleBackend struct {
+	r *AlertRuleReconciler
+}
+
+func (b *prometheusRuleBackend) Name() string {
+	return monitoringv1.BackendPrometheusRule
+}
+
+func (b *prometheusRuleBackend) Available(_ context.Context) (string, error) {
+	available, err := b.r.prometheusRuleCRDAvailable()
+	if err != nil || available {
+		return "", err
+	}
+
+	return fmt.Sprintf("CRD %s is not installed", prometheusRuleCRDName), nil
+}
+
+func (b *prometheusRuleBackend) Publish(ctx context.Context, alertRule *monitoringv1.AlertRule) (publishResult, error) {
+	if !b.r.Aggregate {
+		prometheusRule := b.r.createPrometheusRule(ctx, alertRule)
+		drift, err := b.r.reconcilePrometheusRule(ctx, alertRule, prometheusRule)
+		return publishResult{drift: drift, ruleHash: ruleHash(prometheusRule)}, err
+	}
+
+	drift, hashes, err := b.r.reconcileAggregatedPrometheusRule(ctx, alertRule.Namespace)
+	if err != nil {
+		return publishResult{}, err
+	}
+
+	return publishResult{drift: drift, ruleHash: hashes[alertRule.Name]}, b.r.deleteStandalonePrometheusRule(ctx, alertRule)
+}
+
+func (b *prometheusRuleBackend) Delete(ctx context.Context, alertRule *monitoringv1.AlertRule, _ string) error {
+	// CRD가 없으면 삭제할 PrometheusRule도 없음
+	if message, err := b.Available(ctx); err != nil || message != "" {
+		return err
+	}
+
+	// 그룹만 바뀐 AlertRule의 PrometheusRule은 Publish가 이미 새 그룹으로 갱신함
+	if alertRule.DeletionTimestamp.IsZero() && b.r.backendFor(alertRule).Name() == b.Name() {
+		return nil
+	}
+
+	if b.r.Aggregate {
+		// 삭제 중인 AlertRule을 제외하고 namespace의 PrometheusRule을 다시 구성
+		if _, _, err := b.r.reconcileAggregatedPrometheusRule(ctx, alertRule.Namespace); err != nil {
+			return err
+		}
+	}
+
+	return b.r.deleteStandalonePrometheusRule(ctx, alertRule)
+}
+
+func (b *prometheusRuleBackend) Reference(ctx context.Context, alertRule *monitoringv1.AlertRule) (*monitoringv1.GeneratedReference, error) {
+	name := alertRule.Name
+	if b.r.Aggregate {
+		name = aggregatedPrometheusRuleName
+	}
+
+	prometheusRule := &unstructured.Unstructured{}
+	prometheusRule.SetGroupVersionKind(prometheusRuleGVK())
+	if err := b.r.Get(ctx, client.ObjectKey{Namespace: alertRule.Namespace, Name: name}, prometheusRule); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	gvk := prometheusRuleGVK()
+	return &monitoringv1.GeneratedReference{
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Namespace:  prometheusRule.GetNamespace(),
+		Name:       prometheusRule.GetName(),
+	}, nil
+}
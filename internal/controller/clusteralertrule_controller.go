@@ -0,0 +1,390 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	monitoringv1 "github.com/Kim-Yukyung/k8s-alert-rule-operator/api/v1"
+)
+
+// clusterPrometheusRulePrefix is prepended to the names of the PrometheusRules of ClusterAlertRules,
+// so they do not collide with the PrometheusRules of AlertRules in the operator namespace
+const clusterPrometheusRulePrefix = "cluster-"
+
+// ClusterAlertRuleReconciler reconciles a ClusterAlertRule object
+type ClusterAlertRuleReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// Namespace is the namespace the PrometheusRules of ClusterAlertRules are created in
+	Namespace string
+
+	// watches watches the generated PrometheusRules once their CRD is installed
+	watches *generatedWatches
+}
+
+// +kubebuilder:rbac:groups=monitoring.example.com,resources=clusteralertrules,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=monitoring.example.com,resources=clusteralertrules/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=monitoring.example.com,resources=clusteralertrules/finalizers,verbs=update
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile publishes the rules of a ClusterAlertRule as a PrometheusRule in the operator namespace.
+func (r *ClusterAlertRuleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := logf.FromContext(ctx)
+
+	clusterAlertRule := &monitoringv1.ClusterAlertRule{}
+	if err := r.Get(ctx, req.NamespacedName, clusterAlertRule); err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("ClusterAlertRule not found, ignoring", "name", req.Name)
+			return ctrl.Result{}, nil
+		}
+		logger.Error(err, "unable to fetch ClusterAlertRule")
+		return ctrl.Result{}, err
+	}
+
+	// ClusterAlertRule이 삭제 중인 경우 PrometheusRule을 정리한 뒤 finalizer 제거
+	if !clusterAlertRule.DeletionTimestamp.IsZero() {
+		return r.finalize(ctx, clusterAlertRule)
+	}
+
+	if !controllerutil.ContainsFinalizer(clusterAlertRule, cleanupFinalizer) {
+		controllerutil.AddFinalizer(clusterAlertRule, cleanupFinalizer)
+		if err := r.Update(ctx, clusterAlertRule); err != nil {
+			logger.Error(err, "unable to add finalizer")
+			return ctrl.Result{}, err
+		}
+	}
+
+	rules := r.rules()
+	ready := metav1.Condition{
+		Type:               "Ready",
+		Status:             metav1.ConditionTrue,
+		Reason:             "RulesPublished",
+		Message:            fmt.Sprintf("Rules have been published to namespace %s", r.Namespace),
+		ObservedGeneration: clusterAlertRule.Generation,
+	}
+
+	// PrometheusRule CRD가 없으면 CRD watch가 다시 큐에 넣을 때까지 대기
+	available, err := rules.prometheusRuleCRDAvailable()
+	if err != nil {
+		logger.Error(err, "unable to check PrometheusRule CRD")
+		return ctrl.Result{}, err
+	}
+	if !available {
+		logger.Info("PrometheusRule CRD not available, skipping rule publication")
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = "BackendUnavailable"
+		ready.Message = fmt.Sprintf("CRD %s is not installed", prometheusRuleCRDName)
+		return ctrl.Result{}, r.updateStatus(ctx, clusterAlertRule, ready, nil)
+	}
+
+	// 템플릿을 렌더링할 수 없으면 rule을 게시하지 않음
+	view := alertRuleView(clusterAlertRule)
+	data, err := rules.templateData(ctx, view)
+	if err != nil {
+		logger.Error(err, "unable to resolve template data")
+		return ctrl.Result{}, err
+	}
+	if _, err := renderAlertRule(view, data); err != nil {
+		logger.Info("Unable to render ClusterAlertRule templates, skipping rule publication", "error", err.Error())
+		r.Recorder.Event(clusterAlertRule, corev1.EventTypeWarning, "RenderFailed", err.Error())
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = "RenderFailed"
+		ready.Message = err.Error()
+		return ctrl.Result{}, r.updateStatus(ctx, clusterAlertRule, ready, nil)
+	}
+
+	// 테스트가 실패하면 rule을 게시하지 않음
+	if len(view.Spec.Tests) > 0 {
+		failures, err := rules.runRuleTests(ctx, view)
+		if err != nil {
+			failures = []string{err.Error()}
+		}
+		if len(failures) > 0 {
+			message := strings.Join(failures, "; ")
+			logger.Info("ClusterAlertRule tests failed, skipping rule publication", "failures", message)
+			r.Recorder.Event(clusterAlertRule, corev1.EventTypeWarning, "TestsFailed", message)
+			ready.Status = metav1.ConditionFalse
+			ready.Reason = "TestsFailed"
+			ready.Message = message
+			return ctrl.Result{}, r.updateStatus(ctx, clusterAlertRule, ready, nil)
+		}
+	}
+
+	prometheusRule := r.buildPrometheusRule(ctx, clusterAlertRule, rules)
+	drift, err := r.reconcilePrometheusRule(ctx, clusterAlertRule, prometheusRule, rules)
+	if err != nil {
+		if !isApplyConflict(err) {
+			logger.Error(err, "unable to publish rules")
+			return ctrl.Result{}, err
+		}
+		// 다른 field manager와의 충돌은 status에 기록하고 재시도하지 않음
+		logger.Info("Server-side apply of PrometheusRule conflicts with another field manager", "error", err)
+		ready.Status = metav1.ConditionFalse
+		ready.Reason = "ApplyConflict"
+		ready.Message = fmt.Sprintf("PrometheusRule fields are managed by another field manager: %v", err)
+		return ctrl.Result{}, r.updateStatus(ctx, clusterAlertRule, ready, nil)
+	}
+
+	if drift != "" {
+		logger.Info("Reverted manual change of PrometheusRule", "reason", drift)
+		r.Recorder.Event(clusterAlertRule, corev1.EventTypeWarning, "DriftCorrected", drift)
+		meta.SetStatusCondition(&clusterAlertRule.Status.Conditions, metav1.Condition{
+			Type:               "DriftCorrected",
+			Status:             metav1.ConditionTrue,
+			Reason:             "ManualChangeReverted",
+			Message:            drift,
+			ObservedGeneration: clusterAlertRule.Generation,
+		})
+	}
+
+	if err := r.updateStatus(ctx, clusterAlertRule, ready, prometheusRule); err != nil {
+		logger.Error(err, "unable to update ClusterAlertRule status")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// finalize removes the PrometheusRule of a deleted ClusterAlertRule and then removes the finalizer.
+// While cleanup fails the finalizer is kept and a CleanupBlocked condition explains why.
+func (r *ClusterAlertRuleReconciler) finalize(ctx context.Context, clusterAlertRule *monitoringv1.ClusterAlertRule) (ctrl.Result, error) {
+	logger := logf.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(clusterAlertRule, cleanupFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	rules := r.rules()
+	available, err := rules.prometheusRuleCRDAvailable()
+	if err == nil && available {
+		err = rules.deletePrometheusRule(ctx, r.Namespace, clusterPrometheusRuleName(clusterAlertRule))
+	}
+	if err != nil {
+		message := fmt.Sprintf("Unable to remove the PrometheusRule: %v", err)
+		r.Recorder.Event(clusterAlertRule, corev1.EventTypeWarning, "CleanupBlocked", message)
+		meta.SetStatusCondition(&clusterAlertRule.Status.Conditions, metav1.Condition{
+			Type:               "CleanupBlocked",
+			Status:             metav1.ConditionTrue,
+			Reason:             "BackendCleanupFailed",
+			Message:            message,
+			ObservedGeneration: clusterAlertRule.Generation,
+		})
+		if statusErr := r.Status().Update(ctx, clusterAlertRule); statusErr != nil {
+			logger.Error(statusErr, "unable to update ClusterAlertRule status")
+		}
+		return ctrl.Result{}, err
+	}
+
+	controllerutil.RemoveFinalizer(clusterAlertRule, cleanupFinalizer)
+	if err := r.Update(ctx, clusterAlertRule); err != nil {
+		logger.Error(err, "unable to remove finalizer")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// reconcilePrometheusRule server-side applies the PrometheusRule of a ClusterAlertRule. Like for
+// AlertRules, a change of the PrometheusRule while the ClusterAlertRule kept its generation is
+// reverted, and the returned string describes it.
+func (r *ClusterAlertRuleReconciler) reconcilePrometheusRule(ctx context.Context, clusterAlertRule *monitoringv1.ClusterAlertRule,
+	desired *unstructured.Unstructured, rules *AlertRuleReconciler) (string, error) {
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(prometheusRuleGVK())
+	err := r.Get(ctx, client.ObjectKeyFromObject(desired), existing)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return "", fmt.Errorf("unable to fetch PrometheusRule: %w", err)
+	}
+
+	drift := ""
+	if apierrors.IsNotFound(err) {
+		// 같은 generation으로 이미 생성했던 PrometheusRule이 없어졌다면 수동 삭제로 판단
+		ready := meta.FindStatusCondition(clusterAlertRule.Status.Conditions, "Ready")
+		if ready != nil && ready.Status == metav1.ConditionTrue && ready.ObservedGeneration == clusterAlertRule.Generation {
+			drift = fmt.Sprintf("PrometheusRule %s was deleted manually and has been recreated", desired.GetName())
+		}
+	} else {
+		sameGeneration := existing.GetAnnotations()[generationAnnotation] == strconv.FormatInt(clusterAlertRule.Generation, 10)
+		inSync := prometheusRuleInSync(existing, desired)
+		if sameGeneration && inSync {
+			return "", nil
+		}
+		if sameGeneration {
+			drift = fmt.Sprintf("PrometheusRule %s was modified manually and has been reverted", desired.GetName())
+		}
+	}
+
+	logf.FromContext(ctx).Info("Applying PrometheusRule", "name", desired.GetName(), "namespace", desired.GetNamespace())
+	if err := rules.applyPrometheusRule(ctx, desired, drift != ""); err != nil {
+		return "", fmt.Errorf("unable to apply PrometheusRule: %w", err)
+	}
+
+	return drift, nil
+}
+
+// buildPrometheusRule builds the PrometheusRule of a ClusterAlertRule in the operator namespace
+func (r *ClusterAlertRuleReconciler) buildPrometheusRule(ctx context.Context, clusterAlertRule *monitoringv1.ClusterAlertRule,
+	rules *AlertRuleReconciler) *unstructured.Unstructured {
+	prometheusRule := rules.createPrometheusRule(ctx, alertRuleView(clusterAlertRule))
+	prometheusRule.SetName(clusterPrometheusRuleName(clusterAlertRule))
+	prometheusRule.SetNamespace(r.Namespace)
+
+	// namespace의 PrometheusRule도 cluster 범위의 ClusterAlertRule이 소유할 수 있음
+	prometheusRule.SetOwnerReferences(nil)
+	if err := ctrl.SetControllerReference(clusterAlertRule, prometheusRule, r.Scheme); err != nil {
+		logf.Log.Error(err, "unable to set controller reference")
+	}
+
+	return prometheusRule
+}
+
+// updateStatus records the Ready condition and the applied PrometheusRule, nil when the rules were not
+// published, in the ClusterAlertRule status
+func (r *ClusterAlertRuleReconciler) updateStatus(ctx context.Context, clusterAlertRule *monitoringv1.ClusterAlertRule,
+	ready metav1.Condition, published *unstructured.Unstructured) error {
+	meta.SetStatusCondition(&clusterAlertRule.Status.Conditions, ready)
+
+	view := alertRuleView(clusterAlertRule)
+	clusterAlertRule.Status.ObservedGeneration = clusterAlertRule.Generation
+	clusterAlertRule.Status.RuleGroup = ruleGroupName(view)
+	clusterAlertRule.Status.GeneratedRef = nil
+	if published != nil {
+		gvk := prometheusRuleGVK()
+		clusterAlertRule.Status.GeneratedRef = &monitoringv1.GeneratedReference{
+			APIVersion: gvk.GroupVersion().String(),
+			Kind:       gvk.Kind,
+			Namespace:  published.GetNamespace(),
+			Name:       published.GetName(),
+		}
+		clusterAlertRule.Status.RuleHash = ruleHash(published)
+	}
+	if ready.Status == metav1.ConditionTrue {
+		now := metav1.Now()
+		clusterAlertRule.Status.LastSyncTime = &now
+	}
+
+	return r.Status().Update(ctx, clusterAlertRule)
+}
+
+// rules returns the AlertRuleReconciler building the rules of ClusterAlertRules
+func (r *ClusterAlertRuleReconciler) rules() *AlertRuleReconciler {
+	return &AlertRuleReconciler{Client: r.Client, Scheme: r.Scheme, Recorder: r.Recorder}
+}
+
+// alertRuleView returns the AlertRule the rules of a ClusterAlertRule are built from. It has no
+// namespace, so no namespace matcher is injected, and the fields that only apply to namespaced
+// AlertRules are cleared.
+func alertRuleView(clusterAlertRule *monitoringv1.ClusterAlertRule) *monitoringv1.AlertRule {
+	view := &monitoringv1.AlertRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       clusterAlertRule.Name,
+			Labels:     clusterAlertRule.Labels,
+			Generation: clusterAlertRule.Generation,
+			UID:        clusterAlertRule.UID,
+		},
+		Spec:   *clusterAlertRule.Spec.DeepCopy(),
+		Status: *clusterAlertRule.Status.DeepCopy(),
+	}
+	view.Spec.WorkloadRef = nil
+	view.Spec.WorkloadSelector = nil
+	view.Spec.Backend = ""
+	view.Spec.Routing = nil
+	view.Spec.Maintenance = nil
+
+	return view
+}
+
+// clusterPrometheusRuleName returns the name of the PrometheusRule of a ClusterAlertRule
+func clusterPrometheusRuleName(clusterAlertRule *monitoringv1.ClusterAlertRule) string {
+	return clusterPrometheusRulePrefix + clusterAlertRule.Name
+}
+
+// clusterAlertRulesForCRD enqueues every ClusterAlertRule when the PrometheusRule CRD changes
+// and starts watching the generated PrometheusRules once the CRD is installed
+func (r *ClusterAlertRuleReconciler) clusterAlertRulesForCRD(ctx context.Context, _ client.Object) []reconcile.Request {
+	r.watches.ensure(ctx)
+
+	clusterAlertRules := &monitoringv1.ClusterAlertRuleList{}
+	if err := r.List(ctx, clusterAlertRules); err != nil {
+		logf.FromContext(ctx).Error(err, "unable to list ClusterAlertRules")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(clusterAlertRules.Items))
+	for _, clusterAlertRule := range clusterAlertRules.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: client.ObjectKeyFromObject(&clusterAlertRule),
+		})
+	}
+
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterAlertRuleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&monitoringv1.ClusterAlertRule{})
+
+	// PrometheusRule CRD가 설치되면 모든 ClusterAlertRule을 다시 reconcile
+	crd := &metav1.PartialObjectMetadata{}
+	crd.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   "apiextensions.k8s.io",
+		Version: "v1",
+		Kind:    "CustomResourceDefinition",
+	})
+	b = b.Watches(crd,
+		handler.EnqueueRequestsFromMapFunc(r.clusterAlertRulesForCRD),
+		builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			return obj.GetName() == prometheusRuleCRDName
+		})))
+
+	c, err := b.Named("clusteralertrule").Build(r)
+	if err != nil {
+		return err
+	}
+
+	// 생성한 PrometheusRule의 변경은 CRD가 설치된 뒤부터 감시
+	r.watches = newGeneratedWatches(c, mgr.GetCache(), mgr.GetRESTMapper())
+	r.watches.add(prometheusRuleGVK(), handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(),
+		&monitoringv1.ClusterAlertRule{}, handler.OnlyControllerOwner()))
+	r.watches.ensure(ctrl.LoggerInto(context.Background(), mgr.GetLogger()))
+
+	return nil
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	monitoringv1 "github.com/Kim-Yukyung/k8s-alert-rule-operator/api/v1"
+)
+
+var _ = Describe("ClusterAlertRule Controller", func() {
+	var (
+		clusterAlertRule *monitoringv1.ClusterAlertRule
+		fakeClient       client.Client
+		reconciler       *ClusterAlertRuleReconciler
+		applied          int
+	)
+
+	BeforeEach(func() {
+		clusterAlertRule = &monitoringv1.ClusterAlertRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-pressure", Generation: 1, UID: "uid-1"},
+			Spec: monitoringv1.AlertRuleSpec{
+				Severity: "warning",
+				Rules: []monitoringv1.Rule{{
+					Alert: "NodeMemoryPressure",
+					Expr:  `kube_node_status_condition{condition="MemoryPressure",status="true"} == 1`,
+				}},
+			},
+		}
+
+		applied = 0
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(k8sClient.Scheme()).
+			WithRESTMapper(newTestRESTMapper(true)).
+			WithObjects(clusterAlertRule).
+			WithStatusSubresource(clusterAlertRule).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Apply: func(ctx context.Context, c client.WithWatch, obj runtime.ApplyConfiguration, opts ...client.ApplyOption) error {
+					applied++
+					return nil
+				},
+			}).
+			Build()
+		reconciler = &ClusterAlertRuleReconciler{
+			Client:    fakeClient,
+			Scheme:    k8sClient.Scheme(),
+			Recorder:  record.NewFakeRecorder(10),
+			Namespace: "monitoring",
+		}
+	})
+
+	It("should build a PrometheusRule in the operator namespace owned by the ClusterAlertRule", func() {
+		prometheusRule := reconciler.buildPrometheusRule(ctx, clusterAlertRule, reconciler.rules())
+
+		Expect(prometheusRule.GetName()).To(Equal("cluster-node-pressure"))
+		Expect(prometheusRule.GetNamespace()).To(Equal("monitoring"))
+		owners := prometheusRule.GetOwnerReferences()
+		Expect(owners).To(HaveLen(1))
+		Expect(owners[0].Kind).To(Equal("ClusterAlertRule"))
+		Expect(owners[0].Name).To(Equal("node-pressure"))
+
+		groups, _, err := unstructured.NestedSlice(prometheusRule.Object, "spec", "groups")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(groups).To(HaveLen(1))
+		rules := groups[0].(map[string]interface{})["rules"].([]interface{})
+		Expect(rules).To(HaveLen(1))
+		Expect(rules[0].(map[string]interface{})["expr"]).To(Equal(clusterAlertRule.Spec.Rules[0].Expr))
+	})
+
+	It("should publish the rules and record the PrometheusRule in the status", func() {
+		key := client.ObjectKeyFromObject(clusterAlertRule)
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(applied).To(Equal(1))
+
+		updated := &monitoringv1.ClusterAlertRule{}
+		Expect(fakeClient.Get(ctx, key, updated)).To(Succeed())
+		Expect(updated.Finalizers).To(ContainElement(cleanupFinalizer))
+		Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, "Ready")).To(BeTrue())
+		Expect(updated.Status.GeneratedRef).NotTo(BeNil())
+		Expect(updated.Status.GeneratedRef.Namespace).To(Equal("monitoring"))
+		Expect(updated.Status.GeneratedRef.Name).To(Equal("cluster-node-pressure"))
+		Expect(updated.Status.RuleGroup).To(Equal("node-pressure-group"))
+		Expect(updated.Status.RuleHash).NotTo(BeEmpty())
+	})
+
+	It("should report a missing PrometheusRule CRD", func() {
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(k8sClient.Scheme()).
+			WithRESTMapper(newTestRESTMapper(false)).
+			WithObjects(clusterAlertRule).
+			WithStatusSubresource(clusterAlertRule).
+			Build()
+		reconciler.Client = fakeClient
+
+		key := client.ObjectKeyFromObject(clusterAlertRule)
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		updated := &monitoringv1.ClusterAlertRule{}
+		Expect(fakeClient.Get(ctx, key, updated)).To(Succeed())
+		ready := meta.FindStatusCondition(updated.Status.Conditions, "Ready")
+		Expect(ready).NotTo(BeNil())
+		Expect(ready.Reason).To(Equal("BackendUnavailable"))
+		Expect(reconciler.clusterAlertRulesForCRD(ctx, nil)).To(ConsistOf(reconcile.Request{NamespacedName: key}))
+	})
+})
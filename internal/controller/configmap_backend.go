@@ -0,0 +1,430 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+
+	monitoringv1 "github.com/Kim-Yukyung/k8s-alert-rule-operator/api/v1"
+)
+
+// ruleConfigMapLabel marks the ConfigMap shards holding the rule files of a ConfigMap backend
+const ruleConfigMapLabel = "monitoring.example.com/rule-configmap"
+
+// defaultMaxConfigMapSize keeps the data of a ConfigMap shard well below the 1 MiB object size limit
+const defaultMaxConfigMapSize = 900 * 1024
+
+// ConfigMapConfig configures the ConfigMap backend
+type ConfigMapConfig struct {
+	// Name of the ConfigMap. Additional shards are named <Name>-1, <Name>-2, ...
+	Name string
+
+	// Namespace of the ConfigMap, usually the namespace Prometheus runs in
+	Namespace string
+
+	// MaxSize is the maximum size in bytes of the data of one ConfigMap shard.
+	// Defaults to 900 KiB.
+	MaxSize int
+}
+
+// configMapBackend renders AlertRules of every namespace to Prometheus rule files kept in a
+// ConfigMap, one "<namespace>.rules.yaml" key per namespace. When the data approaches the
+// ConfigMap size limit, rule files are split into "<namespace>_<n>.rules.yaml" keys and
+// spread over several ConfigMap shards. Publishing an AlertRule only renders the rule files
+// of its namespace and rewrites the shards holding them.
+type configMapBackend struct {
+	r      *AlertRuleReconciler
+	config ConfigMapConfig
+}
+
+// ruleFile is a single Prometheus rule file of a ConfigMap shard
+type ruleFile struct {
+	key     string
+	content string
+}
+
+func (b *configMapBackend) Name() string {
+	return monitoringv1.BackendConfigMap
+}
+
+func (b *configMapBackend) Available(_ context.Context) (string, error) {
+	if b.config.Name == "" || b.config.Namespace == "" {
+		return "rule ConfigMap name or namespace is not configured", nil
+	}
+
+	return "", nil
+}
+
+func (b *configMapBackend) Publish(ctx context.Context, alertRule *monitoringv1.AlertRule) (publishResult, error) {
+	hashes, err := b.sync(ctx, alertRule)
+	return publishResult{ruleHash: hashes[alertRule.Name]}, err
+}
+
+func (b *configMapBackend) Delete(ctx context.Context, alertRule *monitoringv1.AlertRule, _ string) error {
+	// 삭제 중이거나 다른 backend로 옮긴 AlertRule을 제외하고 namespace의 rule 파일을 다시 렌더링
+	_, err := b.sync(ctx, alertRule)
+	return err
+}
+
+func (b *configMapBackend) Reference(ctx context.Context, alertRule *monitoringv1.AlertRule) (*monitoringv1.GeneratedReference, error) {
+	shards, err := b.listShards(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// AlertRule의 그룹이 들어 있는 shard 찾기
+	group := ruleGroupName(alertRule)
+	for _, shard := range shards {
+		for key, content := range shard.Data {
+			if ruleFileNamespace(key) == alertRule.Namespace && ruleFileContains(content, group) {
+				return &monitoringv1.GeneratedReference{
+					APIVersion: "v1",
+					Kind:       "ConfigMap",
+					Namespace:  shard.Namespace,
+					Name:       shard.Name,
+				}, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// sync renders the rule files of the namespace of an AlertRule and updates the ConfigMap shards,
+// leaving the rule files of other namespaces where they are and deleting shards no longer needed.
+// The content hashes of the rules of the AlertRules of the namespace are returned. An
+// unpublishableError is returned when the rule group of the AlertRule does not fit in a shard.
+func (b *configMapBackend) sync(ctx context.Context, alertRule *monitoringv1.AlertRule) (ruleHashes, error) {
+	logger := logf.FromContext(ctx)
+
+	// ConfigMap backend가 설정되지 않았으면 정리할 것도 없음
+	if message, _ := b.Available(ctx); message != "" {
+		return nil, nil
+	}
+
+	files, hashes, unpublishable, err := b.renderRuleFiles(ctx, alertRule)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := b.listShards(ctx)
+	if err != nil {
+		return nil, err
+	}
+	current := []map[string]string{}
+	versions := map[string]string{}
+	for _, shard := range existing {
+		i, ok := b.shardIndex(shard.Name)
+		if !ok {
+			continue
+		}
+		for len(current) <= i {
+			current = append(current, map[string]string{})
+		}
+		current[i] = shard.Data
+		versions[shard.Name] = shard.ResourceVersion
+	}
+	shards := placeRuleFiles(current, alertRule.Namespace, files, b.maxSize())
+
+	names := map[string]bool{}
+	for i, data := range shards {
+		name := b.shardName(i)
+
+		// 첫 번째 shard는 비어 있어도 유지하여 Prometheus의 volume mount가 깨지지 않게 함
+		if i > 0 && len(data) == 0 {
+			continue
+		}
+		names[name] = true
+
+		version, ok := versions[name]
+		if ok && equality.Semantic.DeepEqual(current[i], data) {
+			continue
+		}
+
+		logger.Info("Applying rule ConfigMap", "name", name, "namespace", b.config.Namespace, "files", len(data))
+		configMap := corev1ac.ConfigMap(name, b.config.Namespace).
+			WithLabels(map[string]string{
+				"managed-by":       "alert-rule-operator",
+				ruleConfigMapLabel: b.config.Name,
+			}).
+			WithData(data)
+		// 다른 namespace의 rule 파일도 함께 쓰므로 오래된 cache를 기준으로 덮어쓰지 않게 함
+		if ok {
+			configMap.WithResourceVersion(version)
+		}
+		if err := b.r.Apply(ctx, configMap, client.FieldOwner(fieldManager)); err != nil {
+			return nil, fmt.Errorf("unable to apply ConfigMap %s: %w", name, err)
+		}
+	}
+
+	// 더 이상 필요 없는 shard 삭제
+	for i := range existing {
+		if names[existing[i].Name] {
+			continue
+		}
+		logger.Info("Deleting unused rule ConfigMap", "name", existing[i].Name, "namespace", existing[i].Namespace)
+		precondition := client.Preconditions{ResourceVersion: &existing[i].ResourceVersion}
+		if err := client.IgnoreNotFound(b.r.Delete(ctx, &existing[i], precondition)); err != nil {
+			return nil, fmt.Errorf("unable to delete ConfigMap %s: %w", existing[i].Name, err)
+		}
+	}
+
+	if unpublishable != nil {
+		return hashes, unpublishable
+	}
+
+	return hashes, nil
+}
+
+// renderRuleFiles renders the rule files of the namespace of an AlertRule together with the content
+// hashes of the rules of its AlertRules. Rule groups too large for a shard are left out and the Ready
+// condition of their AlertRules explains why; for the given AlertRule an unpublishableError is returned instead.
+func (b *configMapBackend) renderRuleFiles(ctx context.Context, alertRule *monitoringv1.AlertRule) ([]ruleFile, ruleHashes, *unpublishableError, error) {
+	alertRuleList := &monitoringv1.AlertRuleList{}
+	if err := b.r.List(ctx, alertRuleList, client.InNamespace(alertRule.Namespace)); err != nil {
+		return nil, nil, nil, fmt.Errorf("unable to list AlertRules: %w", err)
+	}
+
+	alertRules := []monitoringv1.AlertRule{}
+	for _, candidate := range alertRuleList.Items {
+		if !candidate.DeletionTimestamp.IsZero() || b.r.backendFor(&candidate).Name() != monitoringv1.BackendConfigMap {
+			continue
+		}
+		alertRules = append(alertRules, candidate)
+	}
+
+	groups, hashes, err := b.r.buildRuleGroups(ctx, alertRules)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	files, oversized, err := renderNamespaceRuleFiles(alertRule.Namespace, groups, b.maxSize())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var unpublishable *unpublishableError
+	for i := range alertRules {
+		size, ok := oversized[ruleGroupName(&alertRules[i])]
+		if !ok {
+			continue
+		}
+
+		tooLarge := &unpublishableError{
+			reason: "RuleFileTooLarge",
+			err: fmt.Errorf("rule group %s takes %d bytes, more than the %d bytes a rule ConfigMap can hold",
+				ruleGroupName(&alertRules[i]), size, b.maxSize()),
+		}
+		if alertRules[i].Name == alertRule.Name {
+			unpublishable = tooLarge
+			continue
+		}
+		if err := b.r.reportUnpublished(ctx, &alertRules[i], tooLarge); err != nil {
+			return nil, nil, nil, fmt.Errorf("unable to update status of AlertRule %s: %w", alertRules[i].Name, err)
+		}
+	}
+
+	return files, hashes, unpublishable, nil
+}
+
+// renderNamespaceRuleFiles renders the rule groups of a namespace into "<namespace>.rules.yaml",
+// or into several "<namespace>_<n>.rules.yaml" files when they do not fit in one shard.
+// "_" cannot appear in namespace names, which keeps the keys of different namespaces apart.
+// Rule groups that do not fit in a shard on their own are left out and returned with their size.
+func renderNamespaceRuleFiles(namespace string, groups []interface{}, maxSize int) ([]ruleFile, map[string]int, error) {
+	const header = "groups:\n"
+
+	// 파일 키 길이만큼 여유를 두고 그룹을 나눔
+	budget := maxSize - len(fmt.Sprintf("%s_000.rules.yaml", namespace))
+
+	chunks := [][]interface{}{}
+	oversized := map[string]int{}
+	var chunk []interface{}
+	size := len(header)
+	for _, group := range groups {
+		// Thanos 전용 필드는 Prometheus rule 파일에서 허용되지 않음
+		delete(group.(map[string]interface{}), "partial_response_strategy")
+
+		// 같은 파일의 그룹은 들여쓰기가 같으므로 그룹 하나짜리 파일로 크기를 계산
+		data, err := yaml.Marshal(map[string]interface{}{"groups": []interface{}{group}})
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to encode rule group: %w", err)
+		}
+		if len(data) > budget {
+			// ConfigMap 크기 제한을 넘는 파일은 적용할 수 없으므로 제외
+			oversized[group.(map[string]interface{})["name"].(string)] = len(data)
+			continue
+		}
+		groupSize := len(data) - len(header)
+		if len(chunk) > 0 && size+groupSize > budget {
+			chunks = append(chunks, chunk)
+			chunk, size = nil, len(header)
+		}
+		chunk = append(chunk, group)
+		size += groupSize
+	}
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+
+	files := make([]ruleFile, 0, len(chunks))
+	for i, chunk := range chunks {
+		content, err := yaml.Marshal(map[string]interface{}{"groups": chunk})
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to encode rule file: %w", err)
+		}
+
+		key := fmt.Sprintf("%s.rules.yaml", namespace)
+		if len(chunks) > 1 {
+			key = fmt.Sprintf("%s_%d.rules.yaml", namespace, i)
+		}
+		files = append(files, ruleFile{key: key, content: string(content)})
+	}
+
+	return files, oversized, nil
+}
+
+// placeRuleFiles replaces the rule files of a namespace in the given ConfigMap shards, putting each
+// new rule file in the first shard with room left and adding a shard when none has. The rule files
+// of other namespaces stay in their shards.
+func placeRuleFiles(current []map[string]string, namespace string, files []ruleFile, maxSize int) []map[string]string {
+	shards := make([]map[string]string, 0, len(current))
+	sizes := make([]int, 0, len(current))
+	for _, data := range current {
+		shard := map[string]string{}
+		size := 0
+		for key, content := range data {
+			if ruleFileNamespace(key) == namespace {
+				continue
+			}
+			shard[key] = content
+			size += len(key) + len(content)
+		}
+		shards = append(shards, shard)
+		sizes = append(sizes, size)
+	}
+
+	for _, file := range files {
+		fileSize := len(file.key) + len(file.content)
+		placed := false
+		for i := range shards {
+			if sizes[i]+fileSize <= maxSize {
+				shards[i][file.key] = file.content
+				sizes[i] += fileSize
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			shards = append(shards, map[string]string{file.key: file.content})
+			sizes = append(sizes, fileSize)
+		}
+	}
+
+	// 비어 있어도 첫 번째 shard는 유지하여 Prometheus의 volume mount가 깨지지 않게 함
+	if len(shards) == 0 {
+		shards = append(shards, map[string]string{})
+	}
+
+	return shards
+}
+
+// ruleFileNamespace returns the namespace a rule file key was rendered for
+func ruleFileNamespace(key string) string {
+	name := strings.TrimSuffix(key, ".rules.yaml")
+	if i := strings.Index(name, "_"); i >= 0 {
+		return name[:i]
+	}
+
+	return name
+}
+
+// ruleFileContains reports whether a rule file contains the named rule group
+func ruleFileContains(content, group string) bool {
+	file := struct {
+		Groups []struct {
+			Name string `json:"name"`
+		} `json:"groups"`
+	}{}
+	if err := yaml.Unmarshal([]byte(content), &file); err != nil {
+		return false
+	}
+
+	for _, g := range file.Groups {
+		if g.Name == group {
+			return true
+		}
+	}
+
+	return false
+}
+
+// listShards returns the existing ConfigMap shards of the backend
+func (b *configMapBackend) listShards(ctx context.Context) ([]corev1.ConfigMap, error) {
+	configMaps := &corev1.ConfigMapList{}
+	if err := b.r.List(ctx, configMaps,
+		client.InNamespace(b.config.Namespace),
+		client.MatchingLabels{ruleConfigMapLabel: b.config.Name}); err != nil {
+		return nil, fmt.Errorf("unable to list rule ConfigMaps: %w", err)
+	}
+
+	return configMaps.Items, nil
+}
+
+// shardIndex returns the position of a ConfigMap shard from its name
+func (b *configMapBackend) shardIndex(name string) (int, bool) {
+	if name == b.config.Name {
+		return 0, true
+	}
+
+	suffix, ok := strings.CutPrefix(name, b.config.Name+"-")
+	if !ok {
+		return 0, false
+	}
+	i, err := strconv.Atoi(suffix)
+	if err != nil || i <= 0 {
+		return 0, false
+	}
+
+	return i, true
+}
+
+// shardName returns the name of the i-th ConfigMap shard
+func (b *configMapBackend) shardName(i int) string {
+	if i == 0 {
+		return b.config.Name
+	}
+
+	return fmt.Sprintf("%s-%d", b.config.Name, i)
+}
+
+func (b *configMapBackend) maxSize() int {
+	if b.config.MaxSize > 0 {
+		return b.config.MaxSize
+	}
+
+	return defaultMaxConfigMapSize
+}
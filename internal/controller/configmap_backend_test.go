@@ -0,0 +1,195 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/yaml"
+
+	monitoringv1 "github.com/Kim-Yukyung/k8s-alert-rule-operator/api/v1"
+)
+
+var _ = Describe("ConfigMap backend", func() {
+	var (
+		fakeClient client.Client
+		reconciler *AlertRuleReconciler
+	)
+
+	newAlertRule := func(name, namespace string) *monitoringv1.AlertRule {
+		return &monitoringv1.AlertRule{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Generation: 1},
+			Spec: monitoringv1.AlertRuleSpec{
+				Alert:    fmt.Sprintf("%sDown", name),
+				Expr:     fmt.Sprintf(`up{job=%q} == 0`, name),
+				Severity: "critical",
+				Backend:  monitoringv1.BackendConfigMap,
+				GroupSettings: &monitoringv1.RuleGroupSettings{
+					Interval:                "30s",
+					PartialResponseStrategy: "warn",
+				},
+			},
+		}
+	}
+
+	reconcileAll := func(alertRules ...*monitoringv1.AlertRule) {
+		for _, alertRule := range alertRules {
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(alertRule)})
+			Expect(err).NotTo(HaveOccurred())
+		}
+	}
+
+	listShards := func() map[string]map[string]string {
+		configMaps := &corev1.ConfigMapList{}
+		Expect(fakeClient.List(ctx, configMaps, client.InNamespace("monitoring"))).To(Succeed())
+		shards := map[string]map[string]string{}
+		for _, configMap := range configMaps.Items {
+			shards[configMap.Name] = configMap.Data
+		}
+		return shards
+	}
+
+	BeforeEach(func() {
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(k8sClient.Scheme()).
+			WithRESTMapper(newTestRESTMapper(false)).
+			WithObjects(newAlertRule("checkout", "shop"), newAlertRule("cache", "platform")).
+			WithStatusSubresource(&monitoringv1.AlertRule{}).
+			Build()
+
+		reconciler = &AlertRuleReconciler{
+			Client:    fakeClient,
+			Scheme:    k8sClient.Scheme(),
+			Recorder:  record.NewFakeRecorder(10),
+			ConfigMap: ConfigMapConfig{Name: "prometheus-rules", Namespace: "monitoring"},
+		}
+	})
+
+	It("should render one Prometheus rule file per namespace", func() {
+		checkout := newAlertRule("checkout", "shop")
+		reconcileAll(checkout, newAlertRule("cache", "platform"))
+
+		shards := listShards()
+		Expect(shards).To(HaveLen(1))
+		Expect(shards["prometheus-rules"]).To(HaveKey("shop.rules.yaml"))
+		Expect(shards["prometheus-rules"]).To(HaveKey("platform.rules.yaml"))
+
+		file := struct {
+			Groups []map[string]interface{} `json:"groups"`
+		}{}
+		Expect(yaml.Unmarshal([]byte(shards["prometheus-rules"]["shop.rules.yaml"]), &file)).To(Succeed())
+		Expect(file.Groups).To(HaveLen(1))
+		Expect(file.Groups[0]).To(HaveKeyWithValue("name", "checkout-group"))
+		Expect(file.Groups[0]).To(HaveKeyWithValue("interval", "30s"))
+		Expect(file.Groups[0]).NotTo(HaveKey("partial_response_strategy"))
+
+		updated := &monitoringv1.AlertRule{}
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(checkout), updated)).To(Succeed())
+		Expect(updated.Status.GeneratedRef).To(Equal(&monitoringv1.GeneratedReference{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+			Namespace:  "monitoring",
+			Name:       "prometheus-rules",
+		}))
+	})
+
+	It("should shard rule files over several ConfigMaps near the size limit", func() {
+		for i := 0; i < 3; i++ {
+			Expect(fakeClient.Create(ctx, newAlertRule(fmt.Sprintf("svc%d", i), "shop"))).To(Succeed())
+		}
+		reconciler.ConfigMap.MaxSize = 400
+
+		reconcileAll(newAlertRule("checkout", "shop"), newAlertRule("cache", "platform"))
+
+		shards := listShards()
+		Expect(len(shards)).To(BeNumerically(">", 1))
+		keys := []string{}
+		for name, data := range shards {
+			size := 0
+			for key, content := range data {
+				keys = append(keys, key)
+				size += len(key) + len(content)
+			}
+			Expect(size).To(BeNumerically("<=", 400), "shard %s is too large", name)
+		}
+		Expect(keys).To(ContainElements("shop_0.rules.yaml", "shop_1.rules.yaml", "platform.rules.yaml"))
+
+		By("removing shards no longer needed")
+		reconciler.ConfigMap.MaxSize = 0
+		reconcileAll(newAlertRule("checkout", "shop"), newAlertRule("cache", "platform"))
+		Expect(listShards()).To(HaveLen(1))
+	})
+
+	It("should only render the rule files of the namespace of the AlertRule", func() {
+		reconcileAll(newAlertRule("checkout", "shop"))
+
+		shards := listShards()
+		Expect(shards["prometheus-rules"]).To(HaveKey("shop.rules.yaml"))
+		Expect(shards["prometheus-rules"]).NotTo(HaveKey("platform.rules.yaml"))
+
+		By("keeping the rule files of other namespaces as they are")
+		reconcileAll(newAlertRule("cache", "platform"))
+		platform := listShards()["prometheus-rules"]["platform.rules.yaml"]
+		cache := &monitoringv1.AlertRule{}
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(newAlertRule("cache", "platform")), cache)).To(Succeed())
+		cache.Spec.Expr = `up{job="cache"} < 1`
+		Expect(fakeClient.Update(ctx, cache)).To(Succeed())
+		Expect(fakeClient.Delete(ctx, newAlertRule("checkout", "shop"))).To(Succeed())
+		reconcileAll(newAlertRule("checkout", "shop"))
+
+		shards = listShards()
+		Expect(shards["prometheus-rules"]).NotTo(HaveKey("shop.rules.yaml"))
+		Expect(shards["prometheus-rules"]).To(HaveKeyWithValue("platform.rules.yaml", platform))
+	})
+
+	It("should report a rule group too large for a ConfigMap instead of applying it", func() {
+		reconciler.ConfigMap.MaxSize = 100
+
+		checkout := newAlertRule("checkout", "shop")
+		reconcileAll(checkout)
+
+		Expect(listShards()["prometheus-rules"]).NotTo(HaveKey("shop.rules.yaml"))
+		updated := &monitoringv1.AlertRule{}
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(checkout), updated)).To(Succeed())
+		ready := meta.FindStatusCondition(updated.Status.Conditions, "Ready")
+		Expect(ready).NotTo(BeNil())
+		Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+		Expect(ready.Reason).To(Equal("RuleFileTooLarge"))
+		Expect(ready.Message).To(ContainSubstring("rule group checkout-group"))
+	})
+
+	It("should keep an empty ConfigMap once every AlertRule is deleted", func() {
+		reconcileAll(newAlertRule("checkout", "shop"))
+
+		Expect(fakeClient.Delete(ctx, newAlertRule("checkout", "shop"))).To(Succeed())
+		Expect(fakeClient.Delete(ctx, newAlertRule("cache", "platform"))).To(Succeed())
+		reconcileAll(newAlertRule("checkout", "shop"), newAlertRule("cache", "platform"))
+
+		shards := listShards()
+		Expect(shards).To(HaveLen(1))
+		Expect(shards["prometheus-rules"]).To(BeEmpty())
+	})
+})
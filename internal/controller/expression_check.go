@@ -0,0 +1,351 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	monitoringv1 "github.com/Kim-Yukyung/k8s-alert-rule-operator/api/v1"
+)
+
+// defaultQueryInterval is the interval between two polls of Prometheus when PrometheusConfig sets none
+const defaultQueryInterval = 5 * time.Minute
+
+// defaultHTTPClient is used for the requests to Prometheus, Alertmanager and rulers when no client is
+// configured. Its timeout keeps an unresponsive endpoint from blocking polls and reconciles forever.
+var defaultHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// PrometheusConfig configures the Prometheus HTTP API the expressions of AlertRules are run against
+type PrometheusConfig struct {
+	// URL of Prometheus, e.g. http://prometheus-operated.monitoring:9090.
+	// Expressions are not checked when empty.
+	URL string
+
+	// AlertsURL of the Prometheus or Alertmanager the alert states of AlertRules are read from.
+	// Defaults to URL; alert states are not synced when both are empty.
+	AlertsURL string
+
+	// AlertsSource is the API served at AlertsURL: AlertsSourcePrometheus, the default when empty,
+	// or AlertsSourceAlertmanager
+	AlertsSource string
+
+	// Interval between two polls of Prometheus for every AlertRule. defaultQueryInterval when zero.
+	Interval time.Duration
+
+	// Client used for requests to Prometheus. An HTTP client with a 30s timeout when nil.
+	Client *http.Client
+}
+
+// prometheusResponse is the envelope of every Prometheus HTTP API response
+type prometheusResponse struct {
+	Status    string          `json:"status"`
+	Data      json.RawMessage `json:"data"`
+	ErrorType string          `json:"errorType"`
+	Error     string          `json:"error"`
+}
+
+// Sources of the alert states of AlertRules
+const (
+	// AlertsSourcePrometheus reads the pending and firing alerts of every rule from the
+	// /api/v1/rules API of Prometheus
+	AlertsSourcePrometheus = "Prometheus"
+
+	// AlertsSourceAlertmanager reads the alerts that are neither silenced nor inhibited from the
+	// /api/v2/alerts API of Alertmanager
+	AlertsSourceAlertmanager = "Alertmanager"
+)
+
+// alertsURL returns the URL the alert states are read from, empty when they are not synced
+func (c PrometheusConfig) alertsURL() string {
+	if c.AlertsURL != "" {
+		return c.AlertsURL
+	}
+
+	return c.URL
+}
+
+// pollPrometheus checks the expressions and syncs the alert states of every AlertRule
+// every interval until the manager stops
+func (r *AlertRuleReconciler) pollPrometheus(ctx context.Context) error {
+	interval := r.Prometheus.Interval
+	if interval <= 0 {
+		interval = defaultQueryInterval
+	}
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if r.Prometheus.URL != "" {
+			r.checkExpressions(ctx)
+		}
+		if r.Prometheus.alertsURL() != "" {
+			r.syncAlertStates(ctx)
+		}
+	}, interval)
+	return nil
+}
+
+// checkExpressions runs the published expressions of every AlertRule against Prometheus and
+// records how many series they return and which of their metrics do not exist in the status
+func (r *AlertRuleReconciler) checkExpressions(ctx context.Context) {
+	logger := logf.FromContext(ctx).WithName("expression-check")
+
+	// 존재하는 metric 이름은 한 번만 조회해 모든 AlertRule에 사용
+	metricNames, err := r.prometheusMetricNames(ctx)
+	if err != nil {
+		logger.Error(err, "unable to fetch metric names from Prometheus")
+		return
+	}
+
+	alertRuleList := &monitoringv1.AlertRuleList{}
+	if err := r.List(ctx, alertRuleList); err != nil {
+		logger.Error(err, "unable to list AlertRules")
+		return
+	}
+
+	for i := range alertRuleList.Items {
+		alertRule := &alertRuleList.Items[i]
+		if !alertRule.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		expressions, err := r.checkAlertRuleExpressions(ctx, alertRule, metricNames)
+		if err != nil {
+			logger.Error(err, "unable to check expressions", "alertrule", alertRule.Name, "namespace", alertRule.Namespace)
+			continue
+		}
+		if err := r.updateExpressionStatus(ctx, alertRule, expressions); err != nil {
+			logger.Error(err, "unable to update AlertRule status", "alertrule", alertRule.Name, "namespace", alertRule.Namespace)
+		}
+	}
+}
+
+// checkAlertRuleExpressions runs the expressions of the rules built for an AlertRule, so that the
+// expressions are checked with their templates rendered and namespace matchers injected
+func (r *AlertRuleReconciler) checkAlertRuleExpressions(ctx context.Context, alertRule *monitoringv1.AlertRule,
+	metricNames map[string]bool) ([]monitoringv1.ExpressionStatus, error) {
+	recording, alerting := r.buildRuleSets(ctx, alertRule)
+	rules, err := testRules(append(recording, alerting...))
+	if err != nil {
+		return nil, err
+	}
+
+	expressions := make([]monitoringv1.ExpressionStatus, 0, len(rules))
+	for _, rule := range rules {
+		expression := monitoringv1.ExpressionStatus{Name: rule.alert}
+		if rule.record != "" {
+			expression.Name = rule.record
+		}
+
+		metrics, err := referencedMetrics(rule.expr)
+		if err != nil {
+			expression.Error = err.Error()
+			expressions = append(expressions, expression)
+			continue
+		}
+		for _, metric := range metrics {
+			if !metricNames[metric] {
+				expression.MissingMetrics = append(expression.MissingMetrics, metric)
+			}
+		}
+
+		// 조회에 실패한 expression은 series 수를 알 수 없으므로 zeroSeries로 보고하지 않음
+		series, err := r.querySeries(ctx, rule.expr)
+		if err != nil {
+			expression.Error = err.Error()
+		} else {
+			expression.Series = int32(series)
+			expression.ZeroSeries = series == 0
+		}
+		expressions = append(expressions, expression)
+	}
+
+	return expressions, nil
+}
+
+// updateExpressionStatus records the expression check results in the AlertRule status.
+// The status is patched with optimistic locking so conditions written by Reconcile are not lost.
+func (r *AlertRuleReconciler) updateExpressionStatus(ctx context.Context, alertRule *monitoringv1.AlertRule,
+	expressions []monitoringv1.ExpressionStatus) error {
+	patch := client.MergeFromWithOptions(alertRule.DeepCopy(), client.MergeFromWithOptimisticLock{})
+
+	var missing []string
+	for _, expression := range expressions {
+		for _, metric := range expression.MissingMetrics {
+			missing = append(missing, fmt.Sprintf("%s (%s)", metric, expression.Name))
+		}
+	}
+
+	condition := metav1.Condition{
+		Type:               "MetricsMissing",
+		Status:             metav1.ConditionFalse,
+		Reason:             "MetricsFound",
+		Message:            "Every metric referenced by the expressions exists in Prometheus",
+		ObservedGeneration: alertRule.Generation,
+	}
+	if len(missing) > 0 {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "MetricsNotFound"
+		condition.Message = fmt.Sprintf("Prometheus has no series of %s, the rules can never match", strings.Join(missing, ", "))
+	}
+	meta.SetStatusCondition(&alertRule.Status.Conditions, condition)
+
+	now := metav1.Now()
+	alertRule.Status.Expressions = expressions
+	alertRule.Status.LastQueryTime = &now
+
+	return r.Status().Patch(ctx, alertRule, patch)
+}
+
+// referencedMetrics returns the sorted names of the metrics selected by an expression
+func referencedMetrics(expr string) ([]string, error) {
+	parsed, err := parser.ParseExpr(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	parser.Inspect(parsed, func(node parser.Node, _ []parser.Node) error {
+		selector, ok := node.(*parser.VectorSelector)
+		if !ok {
+			return nil
+		}
+
+		// {__name__="metric"} 형태의 selector도 metric 이름으로 취급
+		name := selector.Name
+		for _, matcher := range selector.LabelMatchers {
+			if name == "" && matcher.Name == labels.MetricName && matcher.Type == labels.MatchEqual {
+				name = matcher.Value
+			}
+		}
+		if name != "" {
+			seen[name] = true
+		}
+		return nil
+	})
+
+	metrics := make([]string, 0, len(seen))
+	for name := range seen {
+		metrics = append(metrics, name)
+	}
+	sort.Strings(metrics)
+
+	return metrics, nil
+}
+
+// querySeries runs an instant query and returns the number of series in its result
+func (r *AlertRuleReconciler) querySeries(ctx context.Context, expr string) (int, error) {
+	data, err := r.prometheusGet(ctx, r.Prometheus.URL, "/api/v1/query", url.Values{"query": {expr}})
+	if err != nil {
+		return 0, err
+	}
+
+	result := struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	}{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return 0, fmt.Errorf("unable to decode query result: %w", err)
+	}
+
+	switch result.ResultType {
+	case "vector", "matrix":
+		var series []json.RawMessage
+		if err := json.Unmarshal(result.Result, &series); err != nil {
+			return 0, fmt.Errorf("unable to decode query result: %w", err)
+		}
+		return len(series), nil
+	default:
+		// scalar, string 결과는 항상 하나의 값
+		return 1, nil
+	}
+}
+
+// prometheusMetricNames returns the names of every metric Prometheus has series of
+func (r *AlertRuleReconciler) prometheusMetricNames(ctx context.Context) (map[string]bool, error) {
+	data, err := r.prometheusGet(ctx, r.Prometheus.URL, "/api/v1/label/"+labels.MetricName+"/values", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return nil, fmt.Errorf("unable to decode metric names: %w", err)
+	}
+
+	metricNames := make(map[string]bool, len(names))
+	for _, name := range names {
+		metricNames[name] = true
+	}
+
+	return metricNames, nil
+}
+
+// prometheusGet sends a GET request to the Prometheus or Alertmanager HTTP API at base and returns
+// the data of the response
+func (r *AlertRuleReconciler) prometheusGet(ctx context.Context, base, path string, params url.Values) (json.RawMessage, error) {
+	target := strings.TrimSuffix(base, "/") + path
+	if len(params) > 0 {
+		target += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := r.Prometheus.Client
+	if httpClient == nil {
+		httpClient = defaultHTTPClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("prometheus request %s failed: %w", path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read prometheus response: %w", err)
+	}
+
+	// 잘못된 쿼리에 대한 4xx 응답도 JSON으로 오류를 담고 있음
+	response := prometheusResponse{}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("prometheus request %s returned %s: %s", path, resp.Status, strings.TrimSpace(string(body)))
+	}
+	if response.Status != "success" {
+		return nil, fmt.Errorf("prometheus request %s failed: %s: %s", path, response.ErrorType, response.Error)
+	}
+
+	return response.Data, nil
+}
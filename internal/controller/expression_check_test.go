@@ -0,0 +1,187 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	monitoringv1 "github.com/Kim-Yukyung/k8s-alert-rule-operator/api/v1"
+)
+
+// fakePrometheus is an in-memory stand-in for the Prometheus HTTP API
+type fakePrometheus struct {
+	mu      sync.Mutex
+	metrics []string
+	series  map[string]int
+	queries []string
+
+	// rules is the data returned by /api/v1/rules
+	rules interface{}
+
+	// alerts is the data returned by the /api/v2/alerts API of Alertmanager, and alertsQuery the
+	// query of the last request to it
+	alerts      interface{}
+	alertsQuery url.Values
+}
+
+func (f *fakePrometheus) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	switch req.URL.Path {
+	case "/api/v1/label/__name__/values":
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"status": "success", "data": f.metrics})
+	case "/api/v1/query":
+		query := req.URL.Query().Get("query")
+		f.queries = append(f.queries, query)
+
+		count, ok := f.series[query]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"status": "error", "errorType": "bad_data", "error": "unexpected query " + query,
+			})
+			return
+		}
+		result := make([]interface{}, 0, count)
+		for i := 0; i < count; i++ {
+			result = append(result, map[string]interface{}{
+				"metric": map[string]string{"instance": fmt.Sprintf("pod-%d", i)},
+				"value":  []interface{}{0, "1"},
+			})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "success",
+			"data":   map[string]interface{}{"resultType": "vector", "result": result},
+		})
+	case "/api/v1/rules":
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"status": "success", "data": f.rules})
+	case "/api/v2/alerts":
+		f.alertsQuery = req.URL.Query()
+		_ = json.NewEncoder(w).Encode(f.alerts)
+	default:
+		http.Error(w, "unsupported request", http.StatusNotFound)
+	}
+}
+
+var _ = Describe("Expression check", func() {
+	var (
+		prometheus *fakePrometheus
+		server     *httptest.Server
+		fakeClient client.Client
+		reconciler *AlertRuleReconciler
+		alertRule  *monitoringv1.AlertRule
+	)
+
+	BeforeEach(func() {
+		prometheus = &fakePrometheus{
+			metrics: []string{"up", "http_requests_total"},
+			series: map[string]int{
+				`sum by (job) (rate(http_requests_total[5m]))`: 2,
+				`up{job="api"} == 0`:                           0,
+			},
+		}
+		server = httptest.NewServer(prometheus)
+
+		alertRule = &monitoringv1.AlertRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default", Generation: 1},
+			Spec: monitoringv1.AlertRuleSpec{
+				Rules: []monitoringv1.Rule{
+					{Record: "job:http_requests:rate5m", Expr: `sum by (job) (rate(http_requests_total[5m]))`},
+					{Alert: "ApiDown", Expr: `up{job="api"} == 0`},
+				},
+			},
+		}
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(k8sClient.Scheme()).
+			WithObjects(alertRule).
+			WithStatusSubresource(alertRule).
+			Build()
+		reconciler = &AlertRuleReconciler{
+			Client:     fakeClient,
+			Scheme:     k8sClient.Scheme(),
+			Prometheus: PrometheusConfig{URL: server.URL},
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should record the series count of every rule", func() {
+		reconciler.checkExpressions(ctx)
+
+		updated := &monitoringv1.AlertRule{}
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(alertRule), updated)).To(Succeed())
+		Expect(updated.Status.LastQueryTime).NotTo(BeNil())
+		Expect(updated.Status.Expressions).To(ConsistOf(
+			monitoringv1.ExpressionStatus{Name: "job:http_requests:rate5m", Series: 2},
+			monitoringv1.ExpressionStatus{Name: "ApiDown", Series: 0, ZeroSeries: true},
+		))
+
+		missing := meta.FindStatusCondition(updated.Status.Conditions, "MetricsMissing")
+		Expect(missing).NotTo(BeNil())
+		Expect(missing.Status).To(Equal(metav1.ConditionFalse))
+	})
+
+	It("should report metrics that do not exist and query errors", func() {
+		alertRule.Spec.Rules = append(alertRule.Spec.Rules,
+			monitoringv1.Rule{Alert: "QueueStuck", Expr: `queue_depth{queue="orders"} > 100 and on() up`})
+		Expect(fakeClient.Update(ctx, alertRule)).To(Succeed())
+
+		reconciler.checkExpressions(ctx)
+
+		updated := &monitoringv1.AlertRule{}
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(alertRule), updated)).To(Succeed())
+		Expect(updated.Status.Expressions).To(HaveLen(3))
+		stuck := updated.Status.Expressions[2]
+		Expect(stuck.Name).To(Equal("QueueStuck"))
+		Expect(stuck.MissingMetrics).To(Equal([]string{"queue_depth"}))
+		Expect(stuck.Error).To(ContainSubstring("bad_data"))
+		Expect(stuck.ZeroSeries).To(BeFalse())
+
+		missing := meta.FindStatusCondition(updated.Status.Conditions, "MetricsMissing")
+		Expect(missing).NotTo(BeNil())
+		Expect(missing.Status).To(Equal(metav1.ConditionTrue))
+		Expect(missing.Message).To(ContainSubstring("queue_depth (QueueStuck)"))
+	})
+
+	It("should run the expressions with the namespace matcher injected", func() {
+		reconciler.EnforceNamespaceMatcher = true
+		prometheus.series[`up{job="api",namespace="default"} == 0`] = 1
+
+		reconciler.checkExpressions(ctx)
+
+		Expect(prometheus.queries).To(ContainElement(`up{job="api",namespace="default"} == 0`))
+		updated := &monitoringv1.AlertRule{}
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(alertRule), updated)).To(Succeed())
+		Expect(updated.Status.Expressions).To(ContainElement(monitoringv1.ExpressionStatus{Name: "ApiDown", Series: 1}))
+	})
+})
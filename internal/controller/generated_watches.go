@@ -0,0 +1,93 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// generatedWatches watches the objects a controller generates from third-party CRDs, such as
+// PrometheusRules and AlertmanagerConfigs. The CRDs may be installed after the operator started,
+// so the watch of a kind is only added once its CRD is available.
+type generatedWatches struct {
+	mu         sync.Mutex
+	controller controller.Controller
+	cache      cache.Cache
+	mapper     meta.RESTMapper
+	handlers   map[schema.GroupVersionKind]handler.EventHandler
+	watched    map[schema.GroupVersionKind]bool
+}
+
+func newGeneratedWatches(c controller.Controller, cache cache.Cache, mapper meta.RESTMapper) *generatedWatches {
+	return &generatedWatches{
+		controller: c,
+		cache:      cache,
+		mapper:     mapper,
+		handlers:   map[schema.GroupVersionKind]handler.EventHandler{},
+		watched:    map[schema.GroupVersionKind]bool{},
+	}
+}
+
+// add registers the handler of the generated objects of a kind
+func (w *generatedWatches) add(gvk schema.GroupVersionKind, h handler.EventHandler) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.handlers[gvk] = h
+}
+
+// ensure starts watching every registered kind whose CRD is installed. Kinds whose CRD is
+// still missing are retried on the next call.
+func (w *generatedWatches) ensure(ctx context.Context) {
+	if w == nil {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	logger := logf.FromContext(ctx)
+	for gvk, h := range w.handlers {
+		if w.watched[gvk] {
+			continue
+		}
+		if _, err := w.mapper.RESTMapping(gvk.GroupKind(), gvk.Version); err != nil {
+			logger.Info("CRD not available, not watching generated objects yet", "kind", gvk.Kind, "error", err)
+			continue
+		}
+
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gvk)
+		if err := w.controller.Watch(source.Kind[client.Object](w.cache, obj, h)); err != nil {
+			logger.Error(err, "unable to watch generated objects", "kind", gvk.Kind)
+			continue
+		}
+		logger.Info("Watching generated objects", "kind", gvk.Kind)
+		w.watched[gvk] = true
+	}
+}
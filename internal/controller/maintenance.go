@@ -0,0 +1,464 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/robfig/cron/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	monitoringv1 "github.com/Kim-Yukyung/k8s-alert-rule-operator/api/v1"
+)
+
+// defaultSilenceInterval is the interval between two syncs of the silences when SilenceConfig sets none
+const defaultSilenceInterval = time.Minute
+
+// maxWindowOccurrences bounds the occurrences of a recurring window considered in one sync
+const maxWindowOccurrences = 100
+
+// silenceCreatedBy is the creator of the silences managed by the operator
+const silenceCreatedBy = "alert-rule-operator"
+
+// SilenceConfig configures the Alertmanager the maintenance windows of AlertRules are silenced in
+type SilenceConfig struct {
+	// URL of the Alertmanager, e.g. http://alertmanager-operated.monitoring:9093.
+	// Maintenance windows are not applied when empty.
+	URL string
+
+	// Interval between two syncs of the silences of every AlertRule. defaultSilenceInterval when zero.
+	// Silences of recurring windows are created up to one interval before the window starts.
+	Interval time.Duration
+
+	// Client used for requests to Alertmanager. An HTTP client with a 30s timeout when nil.
+	Client *http.Client
+}
+
+// silence is a silence of the Alertmanager v2 API
+type silence struct {
+	ID        string           `json:"id,omitempty"`
+	Matchers  []silenceMatcher `json:"matchers"`
+	StartsAt  time.Time        `json:"startsAt"`
+	EndsAt    time.Time        `json:"endsAt"`
+	CreatedBy string           `json:"createdBy"`
+	Comment   string           `json:"comment"`
+	Status    *struct {
+		State string `json:"state"`
+	} `json:"status,omitempty"`
+}
+
+// silenceMatcher is a label matcher of a silence
+type silenceMatcher struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	IsRegex bool   `json:"isRegex"`
+	IsEqual bool   `json:"isEqual"`
+}
+
+// maintenanceInterval is a period of a maintenance window
+type maintenanceInterval struct {
+	start, end time.Time
+	comment    string
+}
+
+// interval returns the interval between two syncs of the silences
+func (c SilenceConfig) interval() time.Duration {
+	if c.Interval > 0 {
+		return c.Interval
+	}
+
+	return defaultSilenceInterval
+}
+
+// pollSilences syncs the silences of the maintenance windows of every AlertRule every interval
+// until the manager stops
+func (r *AlertRuleReconciler) pollSilences(ctx context.Context) error {
+	wait.UntilWithContext(ctx, r.syncSilences, r.Silences.interval())
+	return nil
+}
+
+// syncSilences creates the silences of the current and upcoming maintenance windows of every
+// AlertRule in Alertmanager and expires the silences of windows that were removed
+func (r *AlertRuleReconciler) syncSilences(ctx context.Context) {
+	logger := logf.FromContext(ctx).WithName("silences")
+
+	// 모든 AlertRule이 Alertmanager의 silence 목록을 한 번만 조회해 공유
+	silences, err := r.listSilences(ctx)
+	if err != nil {
+		logger.Error(err, "unable to list silences")
+		return
+	}
+
+	alertRuleList := &monitoringv1.AlertRuleList{}
+	if err := r.List(ctx, alertRuleList); err != nil {
+		logger.Error(err, "unable to list AlertRules")
+		return
+	}
+
+	for i := range alertRuleList.Items {
+		alertRule := &alertRuleList.Items[i]
+		if !alertRule.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		original := alertRule.DeepCopy()
+		if err := r.syncAlertRuleSilences(ctx, alertRule, silences); err != nil {
+			logger.Error(err, "unable to sync silences", "alertrule", alertRule.Name, "namespace", alertRule.Namespace)
+			continue
+		}
+		if equalTimes(original.Status.SilencedUntil, alertRule.Status.SilencedUntil) {
+			continue
+		}
+		patch := client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{})
+		if err := r.Status().Patch(ctx, alertRule, patch); err != nil {
+			logger.Error(err, "unable to update AlertRule status", "alertrule", alertRule.Name, "namespace", alertRule.Namespace)
+		}
+	}
+}
+
+// reconcileSilences syncs the silences of the maintenance windows of an AlertRule and records
+// the end of the current window in its status, which is written by the caller
+func (r *AlertRuleReconciler) reconcileSilences(ctx context.Context, alertRule *monitoringv1.AlertRule) error {
+	silences, err := r.listSilences(ctx)
+	if err != nil {
+		return err
+	}
+
+	return r.syncAlertRuleSilences(ctx, alertRule, silences)
+}
+
+// syncAlertRuleSilences creates a silence per maintenance interval and alerting rule of an AlertRule,
+// expires the silences of the AlertRule no longer desired and sets status.silencedUntil.
+// silences holds every silence of the Alertmanager.
+func (r *AlertRuleReconciler) syncAlertRuleSilences(ctx context.Context, alertRule *monitoringv1.AlertRule, silences []silence) error {
+	logger := logf.FromContext(ctx)
+	now := time.Now()
+
+	intervals, err := maintenanceIntervals(alertRule.Spec.Maintenance, now, r.Silences.interval())
+	if err != nil {
+		return err
+	}
+
+	_, alerting := r.buildRuleSets(ctx, alertRule)
+	rules, err := testRules(alerting)
+	if err != nil {
+		return err
+	}
+	matcherSets := silenceMatcherSets(rules, alertRule)
+
+	var desired []silence
+	alertRule.Status.SilencedUntil = nil
+	for _, interval := range intervals {
+		if len(matcherSets) > 0 && !interval.start.After(now) {
+			if alertRule.Status.SilencedUntil == nil || interval.end.After(alertRule.Status.SilencedUntil.Time) {
+				end := metav1.NewTime(interval.end)
+				alertRule.Status.SilencedUntil = &end
+			}
+		}
+
+		comment := silenceComment(alertRule)
+		if interval.comment != "" {
+			comment += ": " + interval.comment
+		}
+		for _, matchers := range matcherSets {
+			desired = append(desired, silence{
+				Matchers:  matchers,
+				StartsAt:  interval.start,
+				EndsAt:    interval.end,
+				CreatedBy: silenceCreatedBy,
+				Comment:   comment,
+			})
+		}
+	}
+
+	owned := ownedSilences(alertRule, silences)
+	kept := make([]bool, len(owned))
+	for _, want := range desired {
+		found := false
+		for i, existing := range owned {
+			if !kept[i] && silenceMatches(existing, want, now) {
+				kept[i] = true
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+
+		id, err := r.createSilence(ctx, want)
+		if err != nil {
+			return err
+		}
+		logger.Info("Created maintenance silence", "alertrule", alertRule.Name, "namespace", alertRule.Namespace,
+			"silence", id, "startsAt", want.StartsAt, "endsAt", want.EndsAt)
+	}
+
+	// 새 silence를 먼저 만든 뒤 더 이상 필요 없는 silence를 만료시켜 공백이 생기지 않도록 함
+	for i, existing := range owned {
+		if kept[i] {
+			continue
+		}
+		if err := r.expireSilence(ctx, existing.ID); err != nil {
+			return err
+		}
+		logger.Info("Expired maintenance silence", "alertrule", alertRule.Name, "namespace", alertRule.Namespace, "silence", existing.ID)
+	}
+
+	return nil
+}
+
+// expireSilences expires every silence created for the maintenance windows of an AlertRule
+func (r *AlertRuleReconciler) expireSilences(ctx context.Context, alertRule *monitoringv1.AlertRule) error {
+	silences, err := r.listSilences(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, existing := range ownedSilences(alertRule, silences) {
+		if err := r.expireSilence(ctx, existing.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// maintenanceIntervals returns the intervals of the maintenance windows that are active at now or,
+// for recurring windows, start within lookahead. Overlapping occurrences of a recurring window are merged.
+func maintenanceIntervals(windows []monitoringv1.MaintenanceWindow, now time.Time, lookahead time.Duration) ([]maintenanceInterval, error) {
+	var intervals []maintenanceInterval
+	for i, window := range windows {
+		if window.Schedule == "" {
+			if window.End == nil || !window.End.After(now) {
+				continue
+			}
+			start := now
+			if window.Start != nil {
+				start = window.Start.Time
+			}
+			if start.Before(window.End.Time) {
+				intervals = append(intervals, maintenanceInterval{start: start, end: window.End.Time, comment: window.Comment})
+			}
+			continue
+		}
+
+		schedule, duration, err := parseMaintenanceSchedule(window)
+		if err != nil {
+			return nil, fmt.Errorf("maintenance[%d]: %w", i, err)
+		}
+
+		var occurrences []maintenanceInterval
+		next := schedule.Next(now.Add(-duration))
+		for n := 0; !next.IsZero() && !next.After(now.Add(lookahead)) && n < maxWindowOccurrences; n++ {
+			// 겹치는 시간은 하나의 silence로 합침
+			if last := len(occurrences) - 1; last >= 0 && !next.After(occurrences[last].end) {
+				occurrences[last].end = next.Add(duration)
+			} else {
+				occurrences = append(occurrences, maintenanceInterval{start: next, end: next.Add(duration), comment: window.Comment})
+			}
+			next = schedule.Next(next)
+		}
+		intervals = append(intervals, occurrences...)
+	}
+
+	return intervals, nil
+}
+
+// parseMaintenanceSchedule parses the cron schedule, time zone and duration of a recurring maintenance window
+func parseMaintenanceSchedule(window monitoringv1.MaintenanceWindow) (cron.Schedule, time.Duration, error) {
+	spec := window.Schedule
+	if window.TimeZone != "" {
+		spec = fmt.Sprintf("CRON_TZ=%s %s", window.TimeZone, spec)
+	}
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid schedule: %w", err)
+	}
+
+	duration, err := model.ParseDuration(window.Duration)
+	if err != nil || duration <= 0 {
+		return nil, 0, fmt.Errorf("invalid duration %q", window.Duration)
+	}
+
+	return schedule, time.Duration(duration), nil
+}
+
+// silenceMatcherSets returns the distinct matchers of the silences of the alerting rules of an AlertRule.
+// Like the live alert state, an alert belongs to a rule when its alertname and its alertRuleLabel match,
+// whatever labels the expression of the rule keeps.
+func silenceMatcherSets(rules []testRule, alertRule *monitoringv1.AlertRule) [][]silenceMatcher {
+	seen := map[string]bool{}
+	var sets [][]silenceMatcher
+	for _, rule := range rules {
+		if seen[rule.alert] {
+			continue
+		}
+		seen[rule.alert] = true
+		sets = append(sets, []silenceMatcher{
+			{Name: model.AlertNameLabel, Value: rule.alert, IsEqual: true},
+			{Name: alertRuleLabel, Value: alertRuleIdentity(alertRule), IsEqual: true},
+		})
+	}
+
+	return sets
+}
+
+// matchersKey returns a string identifying a sorted set of silence matchers
+func matchersKey(matchers []silenceMatcher) string {
+	parts := make([]string, 0, len(matchers))
+	for _, m := range matchers {
+		parts = append(parts, fmt.Sprintf("%s/%t/%t/%q", m.Name, m.IsRegex, m.IsEqual, m.Value))
+	}
+	sort.Strings(parts)
+
+	return strings.Join(parts, ",")
+}
+
+// silenceComment returns the comment prefix identifying the silences of an AlertRule
+func silenceComment(alertRule *monitoringv1.AlertRule) string {
+	return fmt.Sprintf("Maintenance window of AlertRule %s/%s", alertRule.Namespace, alertRule.Name)
+}
+
+// ownedSilences returns the silences created by the operator for an AlertRule that have not expired
+func ownedSilences(alertRule *monitoringv1.AlertRule, silences []silence) []silence {
+	prefix := silenceComment(alertRule)
+
+	var owned []silence
+	for _, s := range silences {
+		if s.CreatedBy != silenceCreatedBy || (s.Status != nil && s.Status.State == "expired") {
+			continue
+		}
+		if s.Comment == prefix || strings.HasPrefix(s.Comment, prefix+": ") {
+			owned = append(owned, s)
+		}
+	}
+
+	return owned
+}
+
+// silenceMatches reports whether an existing silence covers a desired one. The start of an interval
+// that has already begun is not compared, since Alertmanager starts such silences at their creation.
+func silenceMatches(existing, desired silence, now time.Time) bool {
+	if existing.Comment != desired.Comment || !existing.EndsAt.Equal(desired.EndsAt) ||
+		matchersKey(existing.Matchers) != matchersKey(desired.Matchers) {
+		return false
+	}
+	if desired.StartsAt.After(now) {
+		return existing.StartsAt.Equal(desired.StartsAt)
+	}
+
+	return !existing.StartsAt.After(now)
+}
+
+// equalTimes reports whether two optional times are equal
+func equalTimes(a, b *metav1.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.Equal(b)
+}
+
+// listSilences returns every silence of the Alertmanager
+func (r *AlertRuleReconciler) listSilences(ctx context.Context) ([]silence, error) {
+	var silences []silence
+	if err := r.alertmanagerRequest(ctx, http.MethodGet, "/api/v2/silences", nil, &silences); err != nil {
+		return nil, err
+	}
+
+	return silences, nil
+}
+
+// createSilence creates a silence and returns its ID
+func (r *AlertRuleReconciler) createSilence(ctx context.Context, s silence) (string, error) {
+	created := struct {
+		SilenceID string `json:"silenceID"`
+	}{}
+	if err := r.alertmanagerRequest(ctx, http.MethodPost, "/api/v2/silences", s, &created); err != nil {
+		return "", err
+	}
+
+	return created.SilenceID, nil
+}
+
+// expireSilence expires a silence
+func (r *AlertRuleReconciler) expireSilence(ctx context.Context, id string) error {
+	return r.alertmanagerRequest(ctx, http.MethodDelete, "/api/v2/silence/"+url.PathEscape(id), nil, nil)
+}
+
+// alertmanagerRequest sends a request to the Alertmanager v2 API, encoding body and decoding
+// the response into out when they are not nil
+func (r *AlertRuleReconciler) alertmanagerRequest(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(r.Silences.URL, "/")+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	httpClient := r.Silences.Client
+	if httpClient == nil {
+		httpClient = defaultHTTPClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("alertmanager request %s %s failed: %w", method, path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("unable to read alertmanager response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("alertmanager request %s %s returned %s: %s", method, path, resp.Status, strings.TrimSpace(string(data)))
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("unable to decode alertmanager response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	monitoringv1 "github.com/Kim-Yukyung/k8s-alert-rule-operator/api/v1"
+)
+
+// fakeAlertmanager is an in-memory stand-in for the silences of the Alertmanager v2 API
+type fakeAlertmanager struct {
+	mu       sync.Mutex
+	silences []silence
+	nextID   int
+}
+
+func (f *fakeAlertmanager) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case req.Method == http.MethodGet && req.URL.Path == "/api/v2/silences":
+		_ = json.NewEncoder(w).Encode(f.silences)
+	case req.Method == http.MethodPost && req.URL.Path == "/api/v2/silences":
+		created := silence{}
+		if err := json.NewDecoder(req.Body).Decode(&created); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.nextID++
+		created.ID = fmt.Sprintf("silence-%d", f.nextID)
+		created.Status = &struct {
+			State string `json:"state"`
+		}{State: "active"}
+		if created.StartsAt.After(time.Now()) {
+			created.Status.State = "pending"
+		}
+		f.silences = append(f.silences, created)
+		_ = json.NewEncoder(w).Encode(map[string]string{"silenceID": created.ID})
+	case req.Method == http.MethodDelete && strings.HasPrefix(req.URL.Path, "/api/v2/silence/"):
+		id := strings.TrimPrefix(req.URL.Path, "/api/v2/silence/")
+		for i := range f.silences {
+			if f.silences[i].ID == id {
+				f.silences[i].Status.State = "expired"
+				return
+			}
+		}
+		http.Error(w, "silence not found", http.StatusNotFound)
+	default:
+		http.Error(w, "unsupported request", http.StatusNotFound)
+	}
+}
+
+// active returns the silences that have not expired
+func (f *fakeAlertmanager) active() []silence {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var active []silence
+	for _, s := range f.silences {
+		if s.Status.State != "expired" {
+			active = append(active, s)
+		}
+	}
+	return active
+}
+
+var _ = Describe("Maintenance windows", func() {
+	var (
+		alertmanager *fakeAlertmanager
+		server       *httptest.Server
+		fakeClient   client.Client
+		reconciler   *AlertRuleReconciler
+		alertRule    *monitoringv1.AlertRule
+	)
+
+	BeforeEach(func() {
+		alertmanager = &fakeAlertmanager{}
+		server = httptest.NewServer(alertmanager)
+
+		alertRule = &monitoringv1.AlertRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+			Spec: monitoringv1.AlertRuleSpec{
+				Severity: "critical",
+				Rules: []monitoringv1.Rule{
+					{Alert: "ApiDown", Expr: `up{job="api"} == 0`},
+					{Alert: "ApiErrors", Expr: `rate(http_requests_total{code=~"5.."}[5m]) > 1`,
+						Labels: map[string]string{"instance": "{{ $labels.instance }}"}},
+					{Record: "job:up:sum", Expr: "sum by (job) (up)"},
+				},
+			},
+		}
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(k8sClient.Scheme()).
+			WithObjects(alertRule).
+			WithStatusSubresource(alertRule).
+			Build()
+		reconciler = &AlertRuleReconciler{
+			Client:   fakeClient,
+			Scheme:   k8sClient.Scheme(),
+			Silences: SilenceConfig{URL: server.URL, Interval: time.Minute},
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	setMaintenance := func(windows ...monitoringv1.MaintenanceWindow) {
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(alertRule), alertRule)).To(Succeed())
+		alertRule.Spec.Maintenance = windows
+		Expect(fakeClient.Update(ctx, alertRule)).To(Succeed())
+	}
+
+	get := func() *monitoringv1.AlertRule {
+		updated := &monitoringv1.AlertRule{}
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(alertRule), updated)).To(Succeed())
+		return updated
+	}
+
+	It("should silence every alerting rule during a one-off window", func() {
+		end := metav1.NewTime(time.Now().Add(time.Hour).Truncate(time.Second))
+		setMaintenance(monitoringv1.MaintenanceWindow{End: &end, Comment: "database migration"})
+
+		reconciler.syncSilences(ctx)
+
+		silences := alertmanager.active()
+		Expect(silences).To(HaveLen(2))
+		for _, s := range silences {
+			Expect(s.CreatedBy).To(Equal("alert-rule-operator"))
+			Expect(s.Comment).To(Equal("Maintenance window of AlertRule default/api: database migration"))
+			Expect(s.EndsAt.Equal(end.Time)).To(BeTrue())
+		}
+		Expect(silences[0].Matchers).To(Equal([]silenceMatcher{
+			{Name: "alertname", Value: "ApiDown", IsEqual: true},
+			{Name: "alertrule", Value: "default/api", IsEqual: true},
+		}))
+		Expect(silences[1].Matchers).To(Equal([]silenceMatcher{
+			{Name: "alertname", Value: "ApiErrors", IsEqual: true},
+			{Name: "alertrule", Value: "default/api", IsEqual: true},
+		}))
+		Expect(get().Status.SilencedUntil.Equal(&end)).To(BeTrue())
+
+		By("syncing again without changes")
+		reconciler.syncSilences(ctx)
+		Expect(alertmanager.silences).To(HaveLen(2))
+
+		By("removing the window")
+		setMaintenance()
+		reconciler.syncSilences(ctx)
+		Expect(alertmanager.active()).To(BeEmpty())
+		Expect(get().Status.SilencedUntil).To(BeNil())
+	})
+
+	It("should create the silence of a recurring window ahead of its start", func() {
+		setMaintenance(monitoringv1.MaintenanceWindow{Schedule: "* * * * *", Duration: "30s"})
+
+		reconciler.syncSilences(ctx)
+
+		silences := alertmanager.active()
+		Expect(silences).NotTo(BeEmpty())
+		for _, s := range silences {
+			Expect(s.EndsAt.Sub(s.StartsAt)).To(Equal(30 * time.Second))
+			Expect(s.StartsAt.Second()).To(BeZero())
+			Expect(s.StartsAt).To(BeTemporally("<=", time.Now().Add(time.Minute)))
+		}
+	})
+
+	It("should ignore windows that ended and silences of other AlertRules", func() {
+		end := metav1.NewTime(time.Now().Add(-time.Minute))
+		setMaintenance(monitoringv1.MaintenanceWindow{End: &end})
+		alertmanager.silences = []silence{{
+			ID:        "other",
+			Matchers:  []silenceMatcher{{Name: "alertname", Value: "ApiDown", IsEqual: true}},
+			StartsAt:  time.Now(),
+			EndsAt:    time.Now().Add(time.Hour),
+			CreatedBy: "alert-rule-operator",
+			Comment:   "Maintenance window of AlertRule default/api-gateway",
+			Status: &struct {
+				State string `json:"state"`
+			}{State: "active"},
+		}}
+
+		reconciler.syncSilences(ctx)
+
+		Expect(alertmanager.active()).To(HaveLen(1))
+		Expect(alertmanager.active()[0].ID).To(Equal("other"))
+		Expect(get().Status.SilencedUntil).To(BeNil())
+	})
+
+	It("should expire the silences of a deleted AlertRule", func() {
+		end := metav1.NewTime(time.Now().Add(time.Hour))
+		setMaintenance(monitoringv1.MaintenanceWindow{End: &end})
+		reconciler.syncSilences(ctx)
+		Expect(alertmanager.active()).To(HaveLen(2))
+
+		Expect(reconciler.expireSilences(ctx, get())).To(Succeed())
+		Expect(alertmanager.active()).To(BeEmpty())
+	})
+
+	It("should merge overlapping occurrences of a recurring window", func() {
+		now := time.Date(2026, 10, 17, 2, 30, 0, 0, time.UTC)
+		intervals, err := maintenanceIntervals([]monitoringv1.MaintenanceWindow{
+			{Schedule: "0 * * * *", Duration: "90m", TimeZone: "UTC"},
+		}, now, time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(intervals).To(HaveLen(1))
+		Expect(intervals[0].start).To(Equal(time.Date(2026, 10, 17, 2, 0, 0, 0, time.UTC)))
+		Expect(intervals[0].end).To(Equal(time.Date(2026, 10, 17, 4, 30, 0, 0, time.UTC)))
+	})
+})
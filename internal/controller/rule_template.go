@@ -0,0 +1,206 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/prometheus/prometheus/promql/parser"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	monitoringv1 "github.com/Kim-Yukyung/k8s-alert-rule-operator/api/v1"
+	"github.com/Kim-Yukyung/k8s-alert-rule-operator/internal/ruletemplate"
+)
+
+// ruleTemplateData is the data the Go templates in the fields of an AlertRule are rendered with
+type ruleTemplateData struct {
+	// Name of the AlertRule
+	Name string
+	// Namespace of the AlertRule
+	Namespace string
+	// Workload referenced by spec.workloadRef, nil when there is none or it does not exist
+	Workload *templateWorkload
+}
+
+// templateWorkload exposes the metadata of the referenced workload to templates
+type templateWorkload struct {
+	Kind        string
+	Name        string
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+// templateData resolves the data the templates of an AlertRule are rendered with.
+// Nil is returned for AlertRules without templates, which are emitted as they are.
+func (r *AlertRuleReconciler) templateData(ctx context.Context, alertRule *monitoringv1.AlertRule) (*ruleTemplateData, error) {
+	if !hasTemplates(&alertRule.Spec) {
+		return nil, nil
+	}
+
+	data := &ruleTemplateData{
+		Name:      alertRule.Name,
+		Namespace: alertRule.Namespace,
+	}
+
+	ref := alertRule.Spec.WorkloadRef
+	if ref == nil {
+		return data, nil
+	}
+	kind, ok := workloadKinds[ref.Kind]
+	if !ok {
+		return data, nil
+	}
+
+	workload := kind.newObject()
+	if err := r.Get(ctx, client.ObjectKey{Namespace: alertRule.Namespace, Name: ref.Name}, workload); err != nil {
+		if apierrors.IsNotFound(err) {
+			return data, nil
+		}
+		return nil, fmt.Errorf("unable to fetch %s %s: %w", ref.Kind, ref.Name, err)
+	}
+	data.Workload = &templateWorkload{
+		Kind:        ref.Kind,
+		Name:        workload.GetName(),
+		Labels:      workload.GetLabels(),
+		Annotations: workload.GetAnnotations(),
+	}
+
+	return data, nil
+}
+
+// renderAlertRule returns a copy of an AlertRule with the templates in its alert, expr, for,
+// labels and annotations fields and in its rules rendered. A nil data returns the AlertRule itself.
+// Rendered expressions are parsed, since the webhook can only check the template syntax.
+func renderAlertRule(alertRule *monitoringv1.AlertRule, data *ruleTemplateData) (*monitoringv1.AlertRule, error) {
+	if data == nil {
+		return alertRule, nil
+	}
+
+	rendered := alertRule.DeepCopy()
+	spec := &rendered.Spec
+	render := func(name, text string) (string, error) {
+		return renderVariables(name, text, data)
+	}
+
+	// 단일 rule 필드는 rules 목록과 같은 방식으로 렌더링
+	single, err := renderRule(monitoringv1.Rule{
+		Alert:       spec.Alert,
+		Expr:        spec.Expr,
+		For:         spec.For,
+		Annotations: spec.Annotations,
+	}, render)
+	if err != nil {
+		return nil, fmt.Errorf("spec: %w", err)
+	}
+	if err := validateRenderedExpr(spec.Expr, single.Expr); err != nil {
+		return nil, fmt.Errorf("spec: %w", err)
+	}
+	spec.Alert, spec.Expr, spec.For, spec.Annotations = single.Alert, single.Expr, single.For, single.Annotations
+
+	if spec.Labels, err = renderStringMap("labels", spec.Labels, render); err != nil {
+		return nil, fmt.Errorf("spec: %w", err)
+	}
+
+	for i := range spec.Rules {
+		expr := spec.Rules[i].Expr
+		if spec.Rules[i], err = renderRule(spec.Rules[i], render); err != nil {
+			return nil, fmt.Errorf("spec.rules[%d]: %w", i, err)
+		}
+		if err := validateRenderedExpr(expr, spec.Rules[i].Expr); err != nil {
+			return nil, fmt.Errorf("spec.rules[%d]: %w", i, err)
+		}
+	}
+
+	return rendered, nil
+}
+
+// validateRenderedExpr parses an expression rendered from a template
+func validateRenderedExpr(template, rendered string) error {
+	if !ruletemplate.HasVariables(template) {
+		return nil
+	}
+	if _, err := parser.ParseExpr(rendered); err != nil {
+		return fmt.Errorf("rendered expr %q is invalid: %w", rendered, err)
+	}
+
+	return nil
+}
+
+// hasTemplates reports whether any templated field of an AlertRuleSpec references a template variable
+func hasTemplates(spec *monitoringv1.AlertRuleSpec) bool {
+	for _, value := range spec.Labels {
+		if ruletemplate.HasVariables(value) {
+			return true
+		}
+	}
+
+	for _, rule := range specRules(spec) {
+		fields := []string{rule.Alert, rule.Record, rule.Expr, rule.For}
+		for _, value := range rule.Labels {
+			fields = append(fields, value)
+		}
+		for _, value := range rule.Annotations {
+			fields = append(fields, value)
+		}
+		for _, value := range fields {
+			if ruletemplate.HasVariables(value) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// renderVariables renders the template actions and control structures of a field value that
+// reference the variables of an AlertRule. Every other action, such as {{ $labels.instance }},
+// {{ .Value | humanize }} or {{ with query "up" }}, is left as it is for Prometheus to evaluate.
+func renderVariables(name, text string, data *ruleTemplateData) (string, error) {
+	actions, err := ruletemplate.VariableActions(text)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse %s template: %w", name, err)
+	}
+
+	var buf strings.Builder
+	last := 0
+	for _, loc := range actions {
+		action := text[loc[0]:loc[1]]
+		out, err := renderString(name, action, data)
+		if err != nil {
+			return "", err
+		}
+
+		// 액션 단위로 렌더링하므로 {{- 와 -}}의 공백 제거를 직접 적용
+		before := text[last:loc[0]]
+		if strings.HasPrefix(action, "{{- ") {
+			before = strings.TrimRightFunc(before, unicode.IsSpace)
+		}
+		buf.WriteString(before)
+		buf.WriteString(out)
+		last = loc[1]
+		if strings.HasSuffix(action, " -}}") {
+			last += len(text[last:]) - len(strings.TrimLeftFunc(text[last:], unicode.IsSpace))
+		}
+	}
+	buf.WriteString(text[last:])
+
+	return buf.String(), nil
+}
@@ -0,0 +1,624 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/template"
+	"github.com/prometheus/prometheus/tsdb/chunkenc"
+	"github.com/prometheus/prometheus/tsdb/chunks"
+	"github.com/prometheus/prometheus/util/annotations"
+	"k8s.io/apimachinery/pkg/types"
+
+	monitoringv1 "github.com/Kim-Yukyung/k8s-alert-rule-operator/api/v1"
+)
+
+// defaultTestInterval is the interval between the samples of input series and between
+// rule evaluations when neither the test nor the rule group configures one
+const defaultTestInterval = time.Minute
+
+// ruleTestCache holds the results of the rule tests of AlertRules, since the tests are needed by
+// every reconcile, aggregation and silence sync but only change with the AlertRule or its built rules
+type ruleTestCache struct {
+	mu      sync.Mutex
+	results map[types.UID]ruleTestResult
+}
+
+// ruleTestResult is the result of the rule tests of an AlertRule at a generation
+type ruleTestResult struct {
+	generation int64
+	rules      string
+	failures   []string
+	err        error
+}
+
+// get returns the cached result of an AlertRule when it was computed for the same generation and rules
+func (c *ruleTestCache) get(uid types.UID, generation int64, rules string) (ruleTestResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result, ok := c.results[uid]
+	if !ok || result.generation != generation || result.rules != rules {
+		return ruleTestResult{}, false
+	}
+
+	return result, true
+}
+
+// set caches the result of an AlertRule
+func (c *ruleTestCache) set(uid types.UID, result ruleTestResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.results == nil {
+		c.results = map[types.UID]ruleTestResult{}
+	}
+	c.results[uid] = result
+}
+
+// forget drops the cached result of a deleted AlertRule
+func (c *ruleTestCache) forget(uid types.UID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.results, uid)
+}
+
+// testRule is a built Prometheus rule evaluated by the rule tests
+type testRule struct {
+	alert         string
+	record        string
+	expr          string
+	hold          time.Duration
+	keepFiringFor time.Duration
+	labels        map[string]string
+	annotations   map[string]string
+}
+
+// testGroup holds the settings of the rule group that change how its rules are evaluated
+type testGroup struct {
+	interval    time.Duration
+	queryOffset time.Duration
+	limit       int
+}
+
+// testAlert is an active alert of an alerting rule during a rule test. Like in Prometheus, it is
+// pending until it has been active for the hold duration of the rule and firing afterwards.
+type testAlert struct {
+	labels          labels.Labels
+	annotations     labels.Labels
+	activeAt        time.Time
+	keepFiringSince time.Time
+	firing          bool
+}
+
+// runRuleTests evaluates the tests of an AlertRule against the rules built for it, so that
+// workload scoping, templates and namespace isolation are tested as they are published.
+// The returned strings describe the failed expectations; an error means a test is malformed.
+// Results are cached until the generation of the AlertRule or its built rules change.
+func (r *AlertRuleReconciler) runRuleTests(ctx context.Context, alertRule *monitoringv1.AlertRule) ([]string, error) {
+	recording, alerting := r.buildRuleSets(ctx, alertRule)
+	rules, err := testRules(append(recording, alerting...))
+	if err != nil {
+		return nil, err
+	}
+
+	// 같은 generation과 같은 rule이면 이전 결과를 재사용
+	key := fmt.Sprintf("%+v", rules)
+	if result, ok := r.testResults.get(alertRule.UID, alertRule.Generation, key); ok {
+		return result.failures, result.err
+	}
+	failures, err := r.evaluateRuleTests(ctx, alertRule, rules)
+	if ctx.Err() == nil {
+		r.testResults.set(alertRule.UID, ruleTestResult{generation: alertRule.Generation, rules: key, failures: failures, err: err})
+	}
+
+	return failures, err
+}
+
+// evaluateRuleTests runs every test of an AlertRule against the given rules
+func (r *AlertRuleReconciler) evaluateRuleTests(ctx context.Context, alertRule *monitoringv1.AlertRule, rules []testRule) ([]string, error) {
+	group, err := testGroupSettings(alertRule.Spec.GroupSettings)
+	if err != nil {
+		return nil, err
+	}
+
+	var failures []string
+	for i, test := range alertRule.Spec.Tests {
+		name := test.Name
+		if name == "" {
+			name = fmt.Sprintf("tests[%d]", i)
+		}
+
+		testFailures, err := runRuleTest(ctx, rules, &test, group)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		for _, failure := range testFailures {
+			failures = append(failures, fmt.Sprintf("%s: %s", name, failure))
+		}
+	}
+
+	return failures, nil
+}
+
+// testGroupSettings returns the evaluation settings of a rule group
+func testGroupSettings(settings *monitoringv1.RuleGroupSettings) (testGroup, error) {
+	group := testGroup{interval: defaultTestInterval}
+	if settings == nil {
+		return group, nil
+	}
+
+	if settings.Interval != "" {
+		d, err := model.ParseDuration(settings.Interval)
+		if err != nil {
+			return group, fmt.Errorf("invalid group interval: %w", err)
+		}
+		group.interval = time.Duration(d)
+	}
+	if settings.QueryOffset != "" {
+		d, err := model.ParseDuration(settings.QueryOffset)
+		if err != nil {
+			return group, fmt.Errorf("invalid group queryOffset: %w", err)
+		}
+		group.queryOffset = time.Duration(d)
+	}
+	if settings.Limit != nil {
+		group.limit = int(*settings.Limit)
+	}
+
+	return group, nil
+}
+
+// runRuleTest evaluates the rules every group interval from the start of the input series until
+// the last eval time, and checks the firing alerts at every eval time like promtool does
+func runRuleTest(ctx context.Context, rules []testRule, test *monitoringv1.RuleTest, group testGroup) ([]string, error) {
+	interval := defaultTestInterval
+	if test.Interval != "" {
+		d, err := model.ParseDuration(test.Interval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid interval: %w", err)
+		}
+		interval = time.Duration(d)
+	}
+
+	store := newTestStorage()
+	if err := store.load(test.InputSeries, interval); err != nil {
+		return nil, err
+	}
+
+	type check struct {
+		at   time.Duration
+		test monitoringv1.AlertRuleTest
+	}
+	checks := make([]check, 0, len(test.AlertRuleTests))
+	for _, alertTest := range test.AlertRuleTests {
+		at, err := model.ParseDuration(alertTest.EvalTime)
+		if err != nil {
+			return nil, fmt.Errorf("invalid evalTime %q: %w", alertTest.EvalTime, err)
+		}
+		if time.Duration(at) > monitoringv1.MaxTestEvalTime {
+			return nil, fmt.Errorf("evalTime %q exceeds %s", alertTest.EvalTime, model.Duration(monitoringv1.MaxTestEvalTime))
+		}
+		checks = append(checks, check{at: time.Duration(at), test: alertTest})
+	}
+	sort.SliceStable(checks, func(i, j int) bool { return checks[i].at < checks[j].at })
+	if len(checks) == 0 {
+		return nil, nil
+	}
+
+	engine := promql.NewEngine(promql.EngineOpts{
+		MaxSamples:           50000000,
+		Timeout:              time.Minute,
+		EnableAtModifier:     true,
+		EnableNegativeOffset: true,
+	})
+	queryFunc := func(ctx context.Context, qs string, ts time.Time) (promql.Vector, error) {
+		return instantQuery(ctx, engine, store, qs, ts)
+	}
+
+	start := time.Unix(0, 0).UTC()
+	alerts := make([]map[uint64]*testAlert, len(rules))
+	var failures []string
+	next := 0
+	for offset := time.Duration(0); next < len(checks); offset += group.interval {
+		ts := start.Add(offset)
+		queryTime := ts.Add(-group.queryOffset)
+
+		// rule의 결과는 같은 평가 주기의 다음 rule부터 조회할 수 있음
+		for i, rule := range rules {
+			vector, err := queryFunc(ctx, rule.expr, queryTime)
+			if err != nil {
+				return nil, fmt.Errorf("unable to evaluate %q: %w", rule.expr, err)
+			}
+			if rule.record != "" {
+				// limit을 넘은 recording rule은 Prometheus처럼 아무 series도 기록하지 않음
+				if group.limit <= 0 || len(vector) <= group.limit {
+					store.record(rule, vector, queryTime)
+				}
+				continue
+			}
+
+			alerts[i], err = evalAlerts(ctx, rule, vector, ts, alerts[i], queryFunc)
+			if err != nil {
+				return nil, fmt.Errorf("unable to evaluate %q: %w", rule.expr, err)
+			}
+			if group.limit > 0 && len(alerts[i]) > group.limit {
+				alerts[i] = nil
+			}
+			store.recordAlerts(alerts[i], queryTime)
+		}
+
+		// 다음 평가 전까지의 eval time은 이번 평가 결과로 확인
+		for next < len(checks) && checks[next].at < offset+group.interval {
+			failures = append(failures, checkAlerts(rules, alerts, &checks[next].test)...)
+			next++
+		}
+	}
+
+	return failures, nil
+}
+
+// evalAlerts returns the active alerts of an alerting rule after an evaluation, following the
+// alerting rules of Prometheus: an alert fires once it has been active for the hold duration of
+// the rule, and a firing alert whose condition no longer holds keeps firing for keep_firing_for.
+func evalAlerts(ctx context.Context, rule testRule, vector promql.Vector, ts time.Time,
+	previous map[uint64]*testAlert, queryFunc template.QueryFunc) (map[uint64]*testAlert, error) {
+	defs := "{{$labels := .Labels}}{{$externalLabels := .ExternalLabels}}{{$externalURL := .ExternalURL}}{{$value := .Value}}"
+
+	active := make(map[uint64]*testAlert, len(vector))
+	for _, sample := range vector {
+		data := template.AlertTemplateData(sample.Metric.Map(), nil, "", sample)
+		expand := func(text string) string {
+			expander := template.NewTemplateExpander(ctx, defs+text, "__alert_"+rule.alert, data,
+				model.TimeFromUnixNano(ts.UnixNano()), queryFunc, nil, nil)
+			result, err := expander.Expand()
+			if err != nil {
+				return fmt.Sprintf("<error expanding template: %s>", err)
+			}
+			return result
+		}
+
+		builder := labels.NewBuilder(sample.Metric).Del(labels.MetricName)
+		for name, value := range rule.labels {
+			builder.Set(name, expand(value))
+		}
+		builder.Set(labels.AlertName, rule.alert)
+		alertLabels := builder.Labels()
+
+		annotations := labels.NewBuilder(labels.EmptyLabels())
+		for name, value := range rule.annotations {
+			annotations.Set(name, expand(value))
+		}
+
+		h := alertLabels.Hash()
+		if _, ok := active[h]; ok {
+			return nil, fmt.Errorf("vector contains metrics with the same labelset after applying alert labels")
+		}
+
+		// 이전 평가에서 이어지는 alert는 활성화 시각과 상태를 유지
+		alert := &testAlert{labels: alertLabels, annotations: annotations.Labels(), activeAt: ts}
+		if prev, ok := previous[h]; ok {
+			alert.activeAt, alert.firing = prev.activeAt, prev.firing
+		}
+		if !alert.firing && ts.Sub(alert.activeAt) >= rule.hold {
+			alert.firing = true
+		}
+		active[h] = alert
+	}
+
+	// 조건을 더 이상 만족하지 않는 firing alert는 keep_firing_for 동안 유지하고 pending alert는 제거
+	for h, prev := range previous {
+		if _, ok := active[h]; ok || !prev.firing || rule.keepFiringFor <= 0 {
+			continue
+		}
+		if prev.keepFiringSince.IsZero() {
+			prev.keepFiringSince = ts
+		}
+		if ts.Sub(prev.keepFiringSince) < rule.keepFiringFor {
+			active[h] = prev
+		}
+	}
+
+	return active, nil
+}
+
+// checkAlerts compares the firing alerts of the rules named by an AlertRuleTest with the expected alerts
+func checkAlerts(rules []testRule, alerts []map[uint64]*testAlert, test *monitoringv1.AlertRuleTest) []string {
+	var got []string
+	for i, rule := range rules {
+		if rule.alert != test.Alertname {
+			continue
+		}
+		for _, alert := range alerts[i] {
+			if alert.firing {
+				// operator가 붙이는 label은 기대값에 적지 않음
+				alertLabels := labels.NewBuilder(alert.labels).Del(alertRuleLabel).Labels()
+				got = append(got, formatAlert(alertLabels, alert.annotations))
+			}
+		}
+	}
+
+	expected := make([]string, 0, len(test.ExpAlerts))
+	for _, exp := range test.ExpAlerts {
+		expLabels := labels.NewBuilder(labels.FromMap(exp.ExpLabels)).Set(labels.AlertName, test.Alertname).Labels()
+		expected = append(expected, formatAlert(expLabels, labels.FromMap(exp.ExpAnnotations)))
+	}
+
+	sort.Strings(got)
+	sort.Strings(expected)
+	if strings.Join(got, ", ") == strings.Join(expected, ", ") {
+		return nil
+	}
+
+	return []string{fmt.Sprintf("alertname %s at %s: expected [%s], got [%s]",
+		test.Alertname, test.EvalTime, strings.Join(expected, ", "), strings.Join(got, ", "))}
+}
+
+// formatAlert formats the labels and annotations of an alert for comparison and reporting
+func formatAlert(alertLabels, alertAnnotations labels.Labels) string {
+	return fmt.Sprintf("labels:%s annotations:%s", alertLabels.String(), alertAnnotations.String())
+}
+
+// testRules converts the built Prometheus rules back into the rules evaluated by the tests
+func testRules(built []interface{}) ([]testRule, error) {
+	rules := make([]testRule, 0, len(built))
+	for _, b := range built {
+		m, ok := b.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		rule := testRule{
+			labels:      stringMap(m["labels"]),
+			annotations: stringMap(m["annotations"]),
+		}
+		rule.alert, _ = m["alert"].(string)
+		rule.record, _ = m["record"].(string)
+		rule.expr, _ = m["expr"].(string)
+		for key, d := range map[string]*time.Duration{"for": &rule.hold, "keep_firing_for": &rule.keepFiringFor} {
+			value, ok := m[key].(string)
+			if !ok || value == "" {
+				continue
+			}
+			parsed, err := model.ParseDuration(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s duration of %s: %w", key, rule.alert, err)
+			}
+			*d = time.Duration(parsed)
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// stringMap converts a label or annotation map of a built Prometheus rule
+func stringMap(value interface{}) map[string]string {
+	m, _ := value.(map[string]interface{})
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = fmt.Sprint(v)
+	}
+
+	return out
+}
+
+// instantQuery evaluates an expression at a point in time like the Prometheus rule manager does
+func instantQuery(ctx context.Context, engine *promql.Engine, queryable storage.Queryable, qs string, ts time.Time) (promql.Vector, error) {
+	query, err := engine.NewInstantQuery(ctx, queryable, nil, qs, ts)
+	if err != nil {
+		return nil, err
+	}
+	defer query.Close()
+
+	result := query.Exec(ctx)
+	if result.Err != nil {
+		return nil, result.Err
+	}
+	switch v := result.Value.(type) {
+	case promql.Vector:
+		return v, nil
+	case promql.Scalar:
+		return promql.Vector{promql.Sample{T: v.T, F: v.V, Metric: labels.EmptyLabels()}}, nil
+	default:
+		return nil, fmt.Errorf("rule result is not a vector or scalar")
+	}
+}
+
+// testsFailing reports whether the tests declared by an AlertRule fail, in which case its rules
+// are left out of the rule groups shared with other AlertRules
+func (r *AlertRuleReconciler) testsFailing(ctx context.Context, alertRule *monitoringv1.AlertRule) bool {
+	if len(alertRule.Spec.Tests) == 0 {
+		return false
+	}
+
+	failures, err := r.runRuleTests(ctx, alertRule)
+	return err != nil || len(failures) > 0
+}
+
+// testStorage is an in-memory storage.Queryable holding the series of a rule test
+type testStorage struct {
+	series map[uint64]*testSeries
+}
+
+// testSeries is a series of a testStorage with its samples in time order
+type testSeries struct {
+	labels  labels.Labels
+	samples []chunks.Sample
+}
+
+func newTestStorage() *testStorage {
+	return &testStorage{series: map[uint64]*testSeries{}}
+}
+
+// load adds input series in promtool notation with one sample per interval
+func (s *testStorage) load(inputSeries []monitoringv1.TestSeries, interval time.Duration) error {
+	for _, input := range inputSeries {
+		lset, values, err := parser.ParseSeriesDesc(input.Series + " " + input.Values)
+		if err != nil {
+			return fmt.Errorf("invalid input series %s: %w", input.Series, err)
+		}
+
+		for i, value := range values {
+			if value.Omitted {
+				continue
+			}
+			if value.Histogram != nil {
+				return fmt.Errorf("input series %s: native histograms are not supported", input.Series)
+			}
+			s.add(lset, time.Duration(i)*interval, value.Value)
+		}
+	}
+
+	return nil
+}
+
+// record adds the result of a recording rule evaluated at ts
+func (s *testStorage) record(rule testRule, vector promql.Vector, ts time.Time) {
+	for _, sample := range vector {
+		if sample.H != nil {
+			continue
+		}
+		builder := labels.NewBuilder(sample.Metric).Set(labels.MetricName, rule.record)
+		for name, value := range rule.labels {
+			builder.Set(name, value)
+		}
+		s.add(builder.Labels(), ts.Sub(time.Unix(0, 0)), sample.F)
+	}
+}
+
+// recordAlerts adds the ALERTS series of the active alerts of an alerting rule evaluated at ts,
+// so that other rules can query them like in Prometheus
+func (s *testStorage) recordAlerts(alerts map[uint64]*testAlert, ts time.Time) {
+	for _, alert := range alerts {
+		state := "pending"
+		if alert.firing {
+			state = "firing"
+		}
+		lset := labels.NewBuilder(alert.labels).Set(labels.MetricName, "ALERTS").Set("alertstate", state).Labels()
+		s.add(lset, ts.Sub(time.Unix(0, 0)), 1)
+	}
+}
+
+func (s *testStorage) add(lset labels.Labels, at time.Duration, value float64) {
+	series, ok := s.series[lset.Hash()]
+	if !ok {
+		series = &testSeries{labels: lset}
+		s.series[lset.Hash()] = series
+	}
+	series.samples = append(series.samples, testSample{t: at.Milliseconds(), f: value})
+}
+
+func (s *testStorage) Querier(mint, maxt int64) (storage.Querier, error) {
+	return &testQuerier{storage: s, mint: mint, maxt: maxt}, nil
+}
+
+// testQuerier selects the series of a testStorage within a time range
+type testQuerier struct {
+	storage    *testStorage
+	mint, maxt int64
+}
+
+func (q *testQuerier) Select(_ context.Context, _ bool, _ *storage.SelectHints, matchers ...*labels.Matcher) storage.SeriesSet {
+	var selected []storage.Series
+	for _, series := range q.storage.series {
+		matches := true
+		for _, matcher := range matchers {
+			if !matcher.Matches(series.labels.Get(matcher.Name)) {
+				matches = false
+				break
+			}
+		}
+		if !matches {
+			continue
+		}
+
+		var samples []chunks.Sample
+		for _, sample := range series.samples {
+			if sample.T() >= q.mint && sample.T() <= q.maxt {
+				samples = append(samples, sample)
+			}
+		}
+		selected = append(selected, storage.NewListSeries(series.labels, samples))
+	}
+
+	// PromQL은 정렬된 series를 기대함
+	sort.Slice(selected, func(i, j int) bool { return labels.Compare(selected[i].Labels(), selected[j].Labels()) < 0 })
+
+	return &testSeriesSet{series: selected, index: -1}
+}
+
+func (q *testQuerier) LabelValues(context.Context, string, *storage.LabelHints, ...*labels.Matcher) ([]string, annotations.Annotations, error) {
+	return nil, nil, nil
+}
+
+func (q *testQuerier) LabelNames(context.Context, *storage.LabelHints, ...*labels.Matcher) ([]string, annotations.Annotations, error) {
+	return nil, nil, nil
+}
+
+func (q *testQuerier) Close() error {
+	return nil
+}
+
+// testSeriesSet iterates over the series selected by a testQuerier
+type testSeriesSet struct {
+	series []storage.Series
+	index  int
+}
+
+func (s *testSeriesSet) Next() bool {
+	s.index++
+	return s.index < len(s.series)
+}
+
+func (s *testSeriesSet) At() storage.Series {
+	return s.series[s.index]
+}
+
+func (s *testSeriesSet) Err() error {
+	return nil
+}
+
+func (s *testSeriesSet) Warnings() annotations.Annotations {
+	return nil
+}
+
+// testSample is a float sample of a testSeries
+type testSample struct {
+	t int64
+	f float64
+}
+
+func (s testSample) T() int64                      { return s.t }
+func (s testSample) F() float64                    { return s.f }
+func (s testSample) H() *histogram.Histogram       { return nil }
+func (s testSample) FH() *histogram.FloatHistogram { return nil }
+func (s testSample) Type() chunkenc.ValueType      { return chunkenc.ValFloat }
+func (s testSample) Copy() chunks.Sample           { return s }
@@ -0,0 +1,237 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+
+	monitoringv1 "github.com/Kim-Yukyung/k8s-alert-rule-operator/api/v1"
+)
+
+// RulerConfig configures the ruler HTTP API backend shared by Grafana Mimir, Cortex and Loki
+type RulerConfig struct {
+	// URL of the ruler, e.g. http://mimir-ruler:8080 or http://loki:3100/loki.
+	// Rule groups are pushed to <URL>/api/v1/rules/<namespace>.
+	URL string
+
+	// Tenant sent in the X-Scope-OrgID header. Empty for rulers without multi-tenancy.
+	Tenant string
+
+	// Client used for requests to the ruler. An HTTP client with a 30s timeout when nil.
+	Client *http.Client
+}
+
+// rulerBackend publishes rule groups to a ruler HTTP API. The Kubernetes namespace of an
+// AlertRule is used as the ruler namespace, and AlertRules of a namespace sharing a group
+// are pushed together as one rule group.
+type rulerBackend struct {
+	r      *AlertRuleReconciler
+	config RulerConfig
+}
+
+func (b *rulerBackend) Name() string {
+	return monitoringv1.BackendRuler
+}
+
+func (b *rulerBackend) Available(_ context.Context) (string, error) {
+	if b.config.URL == "" {
+		return "ruler URL is not configured", nil
+	}
+
+	return "", nil
+}
+
+func (b *rulerBackend) Publish(ctx context.Context, alertRule *monitoringv1.AlertRule) (publishResult, error) {
+	hashes, err := b.syncGroup(ctx, alertRule.Namespace, ruleGroupName(alertRule))
+	return publishResult{ruleHash: hashes[alertRule.Name]}, err
+}
+
+func (b *rulerBackend) Delete(ctx context.Context, alertRule *monitoringv1.AlertRule, group string) error {
+	// 삭제 중이거나 다른 backend, 그룹으로 옮긴 AlertRule을 제외하고 그룹을 다시 게시하거나 삭제
+	_, err := b.syncGroup(ctx, alertRule.Namespace, group)
+	return err
+}
+
+func (b *rulerBackend) Reference(ctx context.Context, alertRule *monitoringv1.AlertRule) (*monitoringv1.GeneratedReference, error) {
+	group := ruleGroupName(alertRule)
+	existing, err := b.getGroup(ctx, alertRule.Namespace, group)
+	if err != nil || existing == nil {
+		return nil, err
+	}
+
+	return &monitoringv1.GeneratedReference{
+		Kind:      "RuleGroup",
+		Namespace: alertRule.Namespace,
+		Name:      group,
+	}, nil
+}
+
+// syncGroup pushes the rule group built from every Ruler AlertRule of the namespace in the group,
+// or deletes the rule group when no such AlertRule is left. The content hashes of the rules of the
+// AlertRules in the group are returned.
+func (b *rulerBackend) syncGroup(ctx context.Context, namespace, group string) (ruleHashes, error) {
+	logger := logf.FromContext(ctx)
+
+	members, err := b.groupMembers(ctx, namespace, group)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(members) == 0 {
+		logger.Info("Deleting ruler rule group", "namespace", namespace, "group", group)
+		return nil, b.deleteGroup(ctx, namespace, group)
+	}
+
+	// 게시할 수 있는 AlertRule이 하나도 없으면 마지막으로 게시한 그룹을 그대로 유지
+	groups, hashes, err := b.r.buildRuleGroups(ctx, members)
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		logger.Info("Keeping ruler rule group, no AlertRule of the group can be published", "namespace", namespace, "group", group)
+		return hashes, nil
+	}
+	desired := groups[0]
+
+	// YAML 왕복 변환으로 ruler 응답과 같은 형태로 맞춘 뒤 비교
+	body, err := yaml.Marshal(desired)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode rule group: %w", err)
+	}
+	normalized := map[string]interface{}{}
+	if err := yaml.Unmarshal(body, &normalized); err != nil {
+		return nil, fmt.Errorf("unable to decode rule group: %w", err)
+	}
+
+	existing, err := b.getGroup(ctx, namespace, group)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && equality.Semantic.DeepEqual(existing, normalized) {
+		return hashes, nil
+	}
+
+	logger.Info("Pushing ruler rule group", "namespace", namespace, "group", group, "alertrules", len(members))
+	if _, err := b.do(ctx, http.MethodPost, b.rulesURL(namespace), body); err != nil {
+		return nil, err
+	}
+
+	return hashes, nil
+}
+
+// groupMembers returns the AlertRules of a namespace published to the ruler in the given group
+func (b *rulerBackend) groupMembers(ctx context.Context, namespace, group string) ([]monitoringv1.AlertRule, error) {
+	alertRuleList := &monitoringv1.AlertRuleList{}
+	if err := b.r.List(ctx, alertRuleList, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("unable to list AlertRules: %w", err)
+	}
+
+	members := []monitoringv1.AlertRule{}
+	for _, alertRule := range alertRuleList.Items {
+		if !alertRule.DeletionTimestamp.IsZero() || ruleGroupName(&alertRule) != group {
+			continue
+		}
+		if b.r.backendFor(&alertRule).Name() != monitoringv1.BackendRuler {
+			continue
+		}
+		members = append(members, alertRule)
+	}
+
+	return members, nil
+}
+
+// getGroup fetches a rule group from the ruler, returning nil when it does not exist
+func (b *rulerBackend) getGroup(ctx context.Context, namespace, group string) (map[string]interface{}, error) {
+	body, err := b.do(ctx, http.MethodGet, b.groupURL(namespace, group), nil)
+	if err != nil || body == nil {
+		return nil, err
+	}
+
+	existing := map[string]interface{}{}
+	if err := yaml.Unmarshal(body, &existing); err != nil {
+		return nil, fmt.Errorf("unable to decode rule group from ruler: %w", err)
+	}
+
+	return existing, nil
+}
+
+// deleteGroup deletes a rule group from the ruler. A missing rule group is not an error.
+func (b *rulerBackend) deleteGroup(ctx context.Context, namespace, group string) error {
+	_, err := b.do(ctx, http.MethodDelete, b.groupURL(namespace, group), nil)
+	return err
+}
+
+// rulesURL returns the URL of the rules of a ruler namespace
+func (b *rulerBackend) rulesURL(namespace string) string {
+	return fmt.Sprintf("%s/api/v1/rules/%s", strings.TrimSuffix(b.config.URL, "/"), url.PathEscape(namespace))
+}
+
+// groupURL returns the URL of a rule group of a ruler namespace
+func (b *rulerBackend) groupURL(namespace, group string) string {
+	return fmt.Sprintf("%s/%s", b.rulesURL(namespace), url.PathEscape(group))
+}
+
+// do sends a request to the ruler and returns the response body.
+// A 404 response to a GET or DELETE returns a nil body and no error.
+func (b *rulerBackend) do(ctx context.Context, method, target string, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/yaml")
+	}
+	if b.config.Tenant != "" {
+		req.Header.Set("X-Scope-OrgID", b.config.Tenant)
+	}
+
+	httpClient := b.config.Client
+	if httpClient == nil {
+		httpClient = defaultHTTPClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ruler request %s %s failed: %w", method, target, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read ruler response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound && method != http.MethodPost {
+		return nil, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("ruler request %s %s returned %s: %s", method, target, resp.Status, strings.TrimSpace(string(respBody)))
+	}
+
+	return respBody, nil
+}
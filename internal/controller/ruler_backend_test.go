@@ -0,0 +1,287 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/yaml"
+
+	monitoringv1 "github.com/Kim-Yukyung/k8s-alert-rule-operator/api/v1"
+)
+
+// fakeRuler is an in-memory stand-in for the ruler HTTP API
+type fakeRuler struct {
+	mu      sync.Mutex
+	groups  map[string]string
+	posts   int
+	tenants []string
+	fail    bool
+}
+
+func (f *fakeRuler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.tenants = append(f.tenants, req.Header.Get("X-Scope-OrgID"))
+	if f.fail {
+		http.Error(w, "ruler is overloaded", http.StatusInternalServerError)
+		return
+	}
+
+	// /api/v1/rules/{namespace}[/{group}]
+	parts := strings.Split(strings.TrimPrefix(req.URL.Path, "/api/v1/rules/"), "/")
+	switch {
+	case req.Method == http.MethodPost && len(parts) == 1:
+		body, _ := io.ReadAll(req.Body)
+		group := struct {
+			Name string `json:"name"`
+		}{}
+		if err := yaml.Unmarshal(body, &group); err != nil || group.Name == "" {
+			http.Error(w, "invalid rule group", http.StatusBadRequest)
+			return
+		}
+		f.groups[parts[0]+"/"+group.Name] = string(body)
+		f.posts++
+		w.WriteHeader(http.StatusAccepted)
+	case req.Method == http.MethodGet && len(parts) == 2:
+		body, ok := f.groups[parts[0]+"/"+parts[1]]
+		if !ok {
+			http.Error(w, "group does not exist", http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	case req.Method == http.MethodDelete && len(parts) == 2:
+		delete(f.groups, parts[0]+"/"+parts[1])
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		http.Error(w, "unsupported request", http.StatusMethodNotAllowed)
+	}
+}
+
+var _ = Describe("Ruler backend", func() {
+	var (
+		ruler      *fakeRuler
+		server     *httptest.Server
+		fakeClient client.Client
+		reconciler *AlertRuleReconciler
+		key        types.NamespacedName
+	)
+
+	BeforeEach(func() {
+		ruler = &fakeRuler{groups: map[string]string{}}
+		server = httptest.NewServer(ruler)
+
+		alertRule := &monitoringv1.AlertRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "default", Generation: 1},
+			Spec: monitoringv1.AlertRuleSpec{
+				Alert:    "CheckoutDown",
+				Expr:     `up{job="checkout"} == 0`,
+				Severity: "critical",
+				Backend:  monitoringv1.BackendRuler,
+			},
+		}
+		key = client.ObjectKeyFromObject(alertRule)
+
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(k8sClient.Scheme()).
+			WithRESTMapper(newTestRESTMapper(false)).
+			WithObjects(alertRule).
+			WithStatusSubresource(alertRule).
+			Build()
+
+		reconciler = &AlertRuleReconciler{
+			Client:   fakeClient,
+			Scheme:   k8sClient.Scheme(),
+			Recorder: record.NewFakeRecorder(10),
+			Ruler:    RulerConfig{URL: server.URL, Tenant: "team-a", Client: server.Client()},
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should push the rule group and report it in the status", func() {
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(ruler.groups).To(HaveKey("default/checkout-group"))
+		Expect(ruler.groups["default/checkout-group"]).To(ContainSubstring("alert: CheckoutDown"))
+		Expect(ruler.tenants).To(HaveEach("team-a"))
+
+		updated := &monitoringv1.AlertRule{}
+		Expect(fakeClient.Get(ctx, key, updated)).To(Succeed())
+		Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, "Ready")).To(BeTrue())
+		Expect(meta.FindStatusCondition(updated.Status.Conditions, "PrometheusRuleReady")).To(BeNil())
+		Expect(updated.Status.GeneratedRef).To(Equal(&monitoringv1.GeneratedReference{
+			Kind:      "RuleGroup",
+			Namespace: "default",
+			Name:      "checkout-group",
+		}))
+		// 같은 rule은 backend와 관계없이 같은 해시를 가짐
+		Expect(updated.Status.RuleHash).To(Equal(ruleHash(reconciler.createPrometheusRule(ctx, updated))))
+
+		By("not pushing an unchanged rule group again")
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ruler.posts).To(Equal(1))
+	})
+
+	It("should delete the rule group with the AlertRule", func() {
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ruler.groups).To(HaveLen(1))
+
+		Expect(fakeClient.Delete(ctx, &monitoringv1.AlertRule{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+		})).To(Succeed())
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ruler.groups).To(BeEmpty())
+
+		err = fakeClient.Get(ctx, key, &monitoringv1.AlertRule{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should keep the finalizer while the rule group cannot be deleted", func() {
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Delete(ctx, &monitoringv1.AlertRule{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+		})).To(Succeed())
+		ruler.fail = true
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).To(HaveOccurred())
+
+		blocked := &monitoringv1.AlertRule{}
+		Expect(fakeClient.Get(ctx, key, blocked)).To(Succeed())
+		Expect(blocked.Finalizers).To(ContainElement(cleanupFinalizer))
+		condition := meta.FindStatusCondition(blocked.Status.Conditions, "CleanupBlocked")
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Message).To(ContainSubstring("ruler is overloaded"))
+
+		By("removing the finalizer once the ruler recovers")
+		ruler.fail = false
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ruler.groups).To(BeEmpty())
+		err = fakeClient.Get(ctx, key, &monitoringv1.AlertRule{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should remove the rules from the previous rule group when spec.group changes", func() {
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ruler.groups).To(HaveKey("default/checkout-group"))
+
+		alertRule := &monitoringv1.AlertRule{}
+		Expect(fakeClient.Get(ctx, key, alertRule)).To(Succeed())
+		alertRule.Spec.Group = "payments"
+		Expect(fakeClient.Update(ctx, alertRule)).To(Succeed())
+
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ruler.groups).To(HaveLen(1))
+		Expect(ruler.groups).To(HaveKey("default/payments"))
+
+		updated := &monitoringv1.AlertRule{}
+		Expect(fakeClient.Get(ctx, key, updated)).To(Succeed())
+		Expect(updated.Status.PublishedBackend).To(Equal(monitoringv1.BackendRuler))
+		Expect(updated.Status.RuleGroup).To(Equal("payments"))
+	})
+
+	It("should remove the rule group once the AlertRule moves to another backend", func() {
+		reconciler.ConfigMap = ConfigMapConfig{Name: "prometheus-rules", Namespace: "monitoring"}
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ruler.groups).To(HaveLen(1))
+
+		alertRule := &monitoringv1.AlertRule{}
+		Expect(fakeClient.Get(ctx, key, alertRule)).To(Succeed())
+		alertRule.Spec.Backend = monitoringv1.BackendConfigMap
+		Expect(fakeClient.Update(ctx, alertRule)).To(Succeed())
+
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ruler.groups).To(BeEmpty())
+
+		updated := &monitoringv1.AlertRule{}
+		Expect(fakeClient.Get(ctx, key, updated)).To(Succeed())
+		Expect(updated.Status.PublishedBackend).To(Equal(monitoringv1.BackendConfigMap))
+	})
+
+	It("should keep the rule group when no AlertRule of the group passes its tests", func() {
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ruler.posts).To(Equal(1))
+
+		alertRule := &monitoringv1.AlertRule{}
+		Expect(fakeClient.Get(ctx, key, alertRule)).To(Succeed())
+		alertRule.Spec.Tests = []monitoringv1.RuleTest{{
+			Name:        "checkout goes down",
+			InputSeries: []monitoringv1.TestSeries{{Series: `up{job="checkout"}`, Values: "0x10"}},
+			AlertRuleTests: []monitoringv1.AlertRuleTest{
+				{EvalTime: "5m", Alertname: "CheckoutDown"},
+			},
+		}}
+		Expect(fakeClient.Update(ctx, alertRule)).To(Succeed())
+
+		backend := &rulerBackend{r: reconciler, config: reconciler.Ruler}
+		hashes, err := backend.syncGroup(ctx, key.Namespace, "checkout-group")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hashes).NotTo(HaveKey(key.Name))
+		Expect(ruler.posts).To(Equal(1))
+		Expect(ruler.groups["default/checkout-group"]).To(ContainSubstring("alert: CheckoutDown"))
+	})
+
+	It("should return ruler errors so the AlertRule is retried", func() {
+		ruler.fail = true
+
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("ruler is overloaded"))
+	})
+
+	It("should report an unconfigured ruler as BackendUnavailable", func() {
+		reconciler.Ruler = RulerConfig{}
+
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		updated := &monitoringv1.AlertRule{}
+		Expect(fakeClient.Get(ctx, key, updated)).To(Succeed())
+		Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, "BackendUnavailable")).To(BeTrue())
+		Expect(ruler.posts).To(BeZero())
+	})
+})
@@ -0,0 +1,133 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	monitoringv1 "github.com/Kim-Yukyung/k8s-alert-rule-operator/api/v1"
+)
+
+// namespaceLabel is the metric label carrying the namespace of a series
+const namespaceLabel = "namespace"
+
+// namespaceIsolated reports whether the rules of the AlertRules of a namespace are restricted to
+// the metrics of that namespace. The TenantIsolationAnnotation of the Namespace overrides
+// EnforceNamespaceMatcher. Cluster-wide rules, which have no namespace, are never isolated.
+func (r *AlertRuleReconciler) namespaceIsolated(ctx context.Context, namespace string) (bool, error) {
+	// ClusterAlertRule의 rule은 특정 namespace에 속하지 않음
+	if namespace == "" {
+		return false, nil
+	}
+
+	ns := &corev1.Namespace{}
+	if err := r.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+		if apierrors.IsNotFound(err) {
+			return r.EnforceNamespaceMatcher, nil
+		}
+		return false, fmt.Errorf("unable to fetch Namespace %s: %w", namespace, err)
+	}
+
+	switch ns.Annotations[monitoringv1.TenantIsolationAnnotation] {
+	case monitoringv1.TenantIsolationEnforced:
+		return true, nil
+	case monitoringv1.TenantIsolationDisabled:
+		return false, nil
+	}
+
+	return r.EnforceNamespaceMatcher, nil
+}
+
+// enforceNamespace returns a copy of a rule whose vector selectors only select series of the
+// namespace. Like prom-label-proxy, matchers on the namespace label written by the user are
+// replaced, so an expression cannot reach into another namespace.
+func enforceNamespace(rule monitoringv1.Rule, namespace string) (monitoringv1.Rule, error) {
+	expr, err := parser.ParseExpr(rule.Expr)
+	if err != nil {
+		return rule, err
+	}
+
+	matcher := labels.MustNewMatcher(labels.MatchEqual, namespaceLabel, namespace)
+	parser.Inspect(expr, func(node parser.Node, _ []parser.Node) error {
+		vs, ok := node.(*parser.VectorSelector)
+		if !ok {
+			return nil
+		}
+		matchers := make([]*labels.Matcher, 0, len(vs.LabelMatchers)+1)
+		for _, m := range vs.LabelMatchers {
+			if m.Name != namespaceLabel {
+				matchers = append(matchers, m)
+			}
+		}
+		vs.LabelMatchers = append(matchers, matcher)
+		return nil
+	})
+	rule.Expr = expr.String()
+
+	return rule, nil
+}
+
+// isolationFailure describes the first rule of an AlertRule whose expression cannot be restricted to
+// the namespace of the AlertRule. An empty string is returned when the namespace is not isolated or
+// every rule can be restricted.
+func (r *AlertRuleReconciler) isolationFailure(ctx context.Context, alertRule *monitoringv1.AlertRule) (string, error) {
+	isolated, err := r.namespaceIsolated(ctx, alertRule.Namespace)
+	if err != nil || !isolated {
+		return "", err
+	}
+
+	for _, rule := range targetRules(alertRule) {
+		if _, err := enforceNamespace(rule, alertRule.Namespace); err != nil {
+			name := rule.Alert
+			if name == "" {
+				name = rule.Record
+			}
+			return fmt.Sprintf("rule %s: %v", name, err), nil
+		}
+	}
+
+	return "", nil
+}
+
+// alertRulesInNamespace maps a Namespace to reconcile requests for all of its AlertRules,
+// so their rules follow changes of the TenantIsolationAnnotation
+func (r *AlertRuleReconciler) alertRulesInNamespace(ctx context.Context, obj client.Object) []reconcile.Request {
+	alertRules := &monitoringv1.AlertRuleList{}
+	if err := r.List(ctx, alertRules, client.InNamespace(obj.GetName())); err != nil {
+		logf.FromContext(ctx).Error(err, "unable to list AlertRules")
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(alertRules.Items))
+	for _, alertRule := range alertRules.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: alertRule.Namespace, Name: alertRule.Name},
+		})
+	}
+
+	return requests
+}
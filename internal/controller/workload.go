@@ -0,0 +1,157 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	monitoringv1 "github.com/Kim-Yukyung/k8s-alert-rule-operator/api/v1"
+)
+
+// SupportedWorkloadKinds lists the kinds of workloads AlertRules can be generated for
+var SupportedWorkloadKinds = []string{"Deployment", "StatefulSet", "DaemonSet", "Job", "CronJob"}
+
+// workloadKind describes how AlertRules are generated for one kind of workload
+type workloadKind struct {
+	// apiVersion of the workload kind
+	apiVersion string
+	// metricLabel is the kube-state-metrics label carrying the workload name
+	metricLabel string
+	newObject   func() client.Object
+	newList     func() client.ObjectList
+	// ignore reports whether a workload is managed elsewhere and must be skipped
+	ignore func(obj client.Object) bool
+	// defaultSpec returns the built-in alert used when no AlertRuleTemplate exists
+	defaultSpec func(name, namespace string) monitoringv1.AlertRuleSpec
+}
+
+// workloadKinds maps each supported kind to its description
+var workloadKinds = map[string]workloadKind{
+	"Deployment": {
+		apiVersion:  "apps/v1",
+		metricLabel: "deployment",
+		newObject:   func() client.Object { return &appsv1.Deployment{} },
+		newList:     func() client.ObjectList { return &appsv1.DeploymentList{} },
+		defaultSpec: func(name, namespace string) monitoringv1.AlertRuleSpec {
+			return monitoringv1.AlertRuleSpec{
+				Alert:    fmt.Sprintf("%sPodDown", name),
+				Expr:     fmt.Sprintf("kube_deployment_status_replicas_available{deployment=\"%s\", namespace=\"%s\"} == 0", name, namespace),
+				For:      "1m",
+				Severity: "critical",
+				Annotations: map[string]string{
+					"summary":     fmt.Sprintf("Pod %s is down", name),
+					"description": fmt.Sprintf("Pod %s in namespace %s has been down for more than 1 minutes", name, namespace),
+				},
+			}
+		},
+	},
+	"StatefulSet": {
+		apiVersion:  "apps/v1",
+		metricLabel: "statefulset",
+		newObject:   func() client.Object { return &appsv1.StatefulSet{} },
+		newList:     func() client.ObjectList { return &appsv1.StatefulSetList{} },
+		defaultSpec: func(name, namespace string) monitoringv1.AlertRuleSpec {
+			selector := fmt.Sprintf("{statefulset=\"%s\", namespace=\"%s\"}", name, namespace)
+			return monitoringv1.AlertRuleSpec{
+				Alert:    fmt.Sprintf("%sReplicasNotReady", name),
+				Expr:     fmt.Sprintf("kube_statefulset_status_replicas_ready%s < kube_statefulset_replicas%s", selector, selector),
+				For:      "5m",
+				Severity: "critical",
+				Annotations: map[string]string{
+					"summary":     fmt.Sprintf("StatefulSet %s has replicas that are not ready", name),
+					"description": fmt.Sprintf("StatefulSet %s in namespace %s has had fewer ready replicas than desired for more than 5 minutes", name, namespace),
+				},
+			}
+		},
+	},
+	"DaemonSet": {
+		apiVersion:  "apps/v1",
+		metricLabel: "daemonset",
+		newObject:   func() client.Object { return &appsv1.DaemonSet{} },
+		newList:     func() client.ObjectList { return &appsv1.DaemonSetList{} },
+		defaultSpec: func(name, namespace string) monitoringv1.AlertRuleSpec {
+			return monitoringv1.AlertRuleSpec{
+				Alert:    fmt.Sprintf("%sPodsUnavailable", name),
+				Expr:     fmt.Sprintf("kube_daemonset_status_number_unavailable{daemonset=\"%s\", namespace=\"%s\"} > 0", name, namespace),
+				For:      "5m",
+				Severity: "warning",
+				Annotations: map[string]string{
+					"summary":     fmt.Sprintf("DaemonSet %s has unavailable pods", name),
+					"description": fmt.Sprintf("DaemonSet %s in namespace %s has had unavailable pods for more than 5 minutes", name, namespace),
+				},
+			}
+		},
+	},
+	"Job": {
+		apiVersion:  "batch/v1",
+		metricLabel: "job_name",
+		newObject:   func() client.Object { return &batchv1.Job{} },
+		newList:     func() client.ObjectList { return &batchv1.JobList{} },
+		// CronJob이 생성한 Job은 CronJob의 AlertRule로 감시
+		ignore: func(obj client.Object) bool {
+			owner := metav1.GetControllerOf(obj)
+			return owner != nil && owner.Kind == "CronJob"
+		},
+		defaultSpec: func(name, namespace string) monitoringv1.AlertRuleSpec {
+			return monitoringv1.AlertRuleSpec{
+				Alert:    fmt.Sprintf("%sFailed", name),
+				Expr:     fmt.Sprintf("kube_job_status_failed{job_name=\"%s\", namespace=\"%s\"} > 0", name, namespace),
+				Severity: "warning",
+				Annotations: map[string]string{
+					"summary":     fmt.Sprintf("Job %s failed", name),
+					"description": fmt.Sprintf("Job %s in namespace %s has failed pods", name, namespace),
+				},
+			}
+		},
+	},
+	"CronJob": {
+		apiVersion:  "batch/v1",
+		metricLabel: "cronjob",
+		newObject:   func() client.Object { return &batchv1.CronJob{} },
+		newList:     func() client.ObjectList { return &batchv1.CronJobList{} },
+		defaultSpec: func(name, namespace string) monitoringv1.AlertRuleSpec {
+			return monitoringv1.AlertRuleSpec{
+				Alert: fmt.Sprintf("%sJobFailed", name),
+				Expr: fmt.Sprintf("kube_job_status_failed{namespace=\"%s\"} * on (namespace, job_name) group_left() "+
+					"kube_job_owner{owner_kind=\"CronJob\", owner_name=\"%s\", namespace=\"%s\"} > 0", namespace, name, namespace),
+				Severity: "warning",
+				Annotations: map[string]string{
+					"summary":     fmt.Sprintf("A job of CronJob %s failed", name),
+					"description": fmt.Sprintf("A job spawned by CronJob %s in namespace %s has failed pods", name, namespace),
+				},
+			}
+		},
+	},
+}
+
+// workloadAlertRuleName returns the name of the AlertRule generated for a workload. The kind is
+// always part of the name, so workloads of different kinds never share an AlertRule name.
+func workloadAlertRuleName(kind, name string) string {
+	return fmt.Sprintf("%s-%s-alert", name, strings.ToLower(kind))
+}
+
+// legacyDeploymentAlertRuleName returns the "<name>-alert" name AlertRules of Deployments were
+// generated with before the kind became part of the name
+func legacyDeploymentAlertRuleName(name string) string {
+	return fmt.Sprintf("%s-alert", name)
+}
@@ -0,0 +1,677 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	monitoringv1 "github.com/Kim-Yukyung/k8s-alert-rule-operator/api/v1"
+)
+
+// WorkloadReconciler reconciles a workload object (Deployment, StatefulSet, DaemonSet, Job or CronJob)
+type WorkloadReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// Kind of the workloads reconciled, one of SupportedWorkloadKinds
+	Kind string
+
+	// NamespaceSelector restricts AlertRule generation to the namespaces it matches.
+	// Workloads and Namespaces can still opt in or out with monitoringv1.AlertingAnnotation.
+	// A nil selector matches every namespace.
+	NamespaceSelector labels.Selector
+
+	// RulePolicy is the default policy keeping generated AlertRules in sync with their workload,
+	// overridden per workload or Namespace with monitoringv1.RulePolicyAnnotation.
+	// Defaults to monitoringv1.RulePolicyPreserve.
+	RulePolicy string
+}
+
+// alertingSettings holds the alerting configuration of a workload resolved from
+// annotations on the workload and its Namespace
+type alertingSettings struct {
+	enabled          bool
+	severity         string
+	duration         string
+	policy           string
+	maintenanceUntil *metav1.Time
+}
+
+// +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets;daemonsets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=batch,resources=jobs;cronjobs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=monitoring.example.com,resources=alertrules,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=monitoring.example.com,resources=alertruletemplates,verbs=get;list;watch
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+func (r *WorkloadReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	kind := workloadKinds[r.Kind]
+
+	workload := kind.newObject()
+	if err := r.Get(ctx, req.NamespacedName, workload); err != nil {
+		if apierrors.IsNotFound(err) {
+			// workload가 삭제된 경우, 관련 AlertRule도 삭제
+			logger.Info("Workload not found, checking for AlertRule to delete", "kind", r.Kind, "name", req.Name, "namespace", req.Namespace)
+			return r.deleteAlertRuleForWorkload(ctx, req.Namespace, req.Name)
+		}
+		logger.Error(err, "unable to fetch workload", "kind", r.Kind)
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// workload가 삭제 중인 경우 스킵
+	if !workload.GetDeletionTimestamp().IsZero() {
+		logger.Info("Workload is being deleted, skipping reconciliation")
+		return ctrl.Result{}, nil
+	}
+
+	if kind.ignore != nil && kind.ignore(workload) {
+		return ctrl.Result{}, nil
+	}
+
+	namespace := &corev1.Namespace{}
+	if err := r.Get(ctx, client.ObjectKey{Name: workload.GetNamespace()}, namespace); err != nil {
+		logger.Error(err, "unable to fetch Namespace")
+		return ctrl.Result{}, err
+	}
+
+	settings := r.resolveAlertingSettings(ctx, workload, namespace)
+
+	if err := r.migrateLegacyAlertRule(ctx, workload); err != nil {
+		logger.Error(err, "unable to rename AlertRule generated under the former name")
+		return ctrl.Result{}, err
+	}
+
+	alertRuleName := workloadAlertRuleName(r.Kind, workload.GetName())
+
+	// 기존 AlertRule 확인
+	alertRule := &monitoringv1.AlertRule{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: req.Namespace, Name: alertRuleName}, alertRule)
+	if err != nil && !apierrors.IsNotFound(err) {
+		logger.Error(err, "unable to fetch AlertRule")
+		return ctrl.Result{}, err
+	}
+
+	// 알림 생성이 비활성화된 경우, 이 workload가 생성한 AlertRule만 삭제
+	if !settings.enabled {
+		if err == nil && generatedFor(alertRule, r.Kind, workload.GetName()) {
+			logger.Info("Alerting disabled for workload, deleting generated AlertRule", "alertrule", alertRuleName)
+			if err := r.Delete(ctx, alertRule); err != nil && !apierrors.IsNotFound(err) {
+				logger.Error(err, "unable to delete AlertRule")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	desired, buildErr := r.buildAlertRule(ctx, workload, namespace, alertRuleName)
+	if buildErr != nil {
+		logger.Error(buildErr, "unable to build AlertRule")
+		return ctrl.Result{}, buildErr
+	}
+	if desired != nil {
+		applyAlertingSettings(desired, settings)
+		desired.Annotations = map[string]string{
+			monitoringv1.GeneratedSpecHashAnnotation: specHash(&desired.Spec),
+		}
+	}
+
+	if apierrors.IsNotFound(err) {
+		if desired == nil {
+			logger.Info("No AlertRuleTemplate selects workload, skipping AlertRule creation", "kind", r.Kind, "name", workload.GetName())
+			return ctrl.Result{}, nil
+		}
+
+		logger.Info("Creating AlertRule for workload", "kind", r.Kind, "name", workload.GetName(), "namespace", req.Namespace)
+		if err := r.Create(ctx, desired); err != nil {
+			logger.Error(err, "unable to create AlertRule")
+			return ctrl.Result{}, err
+		}
+		logger.Info("Successfully created AlertRule", "alertrule", alertRuleName)
+		return ctrl.Result{}, nil
+	}
+
+	// AlertRule이 이미 존재하는 경우, 정책에 따라 다시 렌더링
+	if err := r.syncGeneratedAlertRule(ctx, workload, alertRule, desired, settings.policy); err != nil {
+		logger.Error(err, "unable to update AlertRule")
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// syncGeneratedAlertRule brings an existing AlertRule in line with the one rendered for its workload,
+// or deletes it when no AlertRuleTemplate selects the workload anymore (desired is nil).
+// AlertRules not generated by the operator are only taken over with RulePolicyAdopt, and
+// AlertRules edited by a user are left alone with RulePolicyPreserve.
+func (r *WorkloadReconciler) syncGeneratedAlertRule(
+	ctx context.Context, workload client.Object, alertRule, desired *monitoringv1.AlertRule, policy string,
+) error {
+	logger := log.FromContext(ctx)
+
+	generated := generatedFor(alertRule, r.Kind, workload.GetName())
+	switch {
+	case !generated && (policy != monitoringv1.RulePolicyAdopt || desired == nil):
+		logger.Info("AlertRule was not generated for workload, leaving it untouched", "alertrule", alertRule.Name)
+		return nil
+	case generated && policy == monitoringv1.RulePolicyPreserve && userEdited(alertRule):
+		logger.Info("AlertRule was edited by a user, preserving the edits", "alertrule", alertRule.Name)
+		return nil
+	}
+
+	if desired == nil {
+		logger.Info("No AlertRuleTemplate selects workload anymore, deleting generated AlertRule", "alertrule", alertRule.Name)
+		return client.IgnoreNotFound(r.Delete(ctx, alertRule))
+	}
+
+	if generatedAlertRuleInSync(alertRule, desired, workload) {
+		return nil
+	}
+
+	if !generated {
+		logger.Info("Adopting AlertRule for workload", "alertrule", alertRule.Name, "kind", r.Kind, "name", workload.GetName())
+		if err := ctrl.SetControllerReference(workload, alertRule, r.Scheme); err != nil {
+			// 다른 controller가 소유한 AlertRule은 가져오지 않음
+			logger.Info("Unable to adopt AlertRule", "alertrule", alertRule.Name, "reason", err.Error())
+			return nil
+		}
+	}
+
+	logger.Info("Updating generated AlertRule", "alertrule", alertRule.Name)
+	if alertRule.Labels == nil {
+		alertRule.Labels = map[string]string{}
+	}
+	for k, v := range desired.Labels {
+		alertRule.Labels[k] = v
+	}
+	if alertRule.Annotations == nil {
+		alertRule.Annotations = map[string]string{}
+	}
+	for k, v := range desired.Annotations {
+		alertRule.Annotations[k] = v
+	}
+	alertRule.Spec = desired.Spec
+
+	return r.Update(ctx, alertRule)
+}
+
+// generatedFor reports whether an AlertRule was generated for the workload of the given kind and name.
+// GeneratedLabel only counts together with a WorkloadRef to that workload, since the AlertRules of
+// other workloads carry it too. AlertRules generated before GeneratedLabel was introduced are
+// recognized by their controller reference.
+func generatedFor(alertRule *monitoringv1.AlertRule, kind, name string) bool {
+	ref := alertRule.Spec.WorkloadRef
+	if alertRule.Labels[monitoringv1.GeneratedLabel] == "true" && ref != nil && ref.Kind == kind && ref.Name == name {
+		return true
+	}
+
+	owner := metav1.GetControllerOf(alertRule)
+	return owner != nil && owner.Kind == kind && owner.Name == name
+}
+
+// migrateLegacyAlertRule renames the AlertRule generated for a Deployment under its former
+// "<name>-alert" name. The spec, labels and annotations are kept, so user edits survive.
+func (r *WorkloadReconciler) migrateLegacyAlertRule(ctx context.Context, workload client.Object) error {
+	if r.Kind != "Deployment" {
+		return nil
+	}
+
+	legacy := &monitoringv1.AlertRule{}
+	key := client.ObjectKey{Namespace: workload.GetNamespace(), Name: legacyDeploymentAlertRuleName(workload.GetName())}
+	if err := r.Get(ctx, key, legacy); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !generatedFor(legacy, r.Kind, workload.GetName()) {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Renaming AlertRule generated for workload", "from", legacy.Name,
+		"to", workloadAlertRuleName(r.Kind, workload.GetName()))
+	renamed := &monitoringv1.AlertRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            workloadAlertRuleName(r.Kind, workload.GetName()),
+			Namespace:       legacy.Namespace,
+			Labels:          legacy.Labels,
+			Annotations:     legacy.Annotations,
+			OwnerReferences: legacy.OwnerReferences,
+		},
+		Spec: legacy.Spec,
+	}
+	if err := r.Create(ctx, renamed); err != nil && !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	return client.IgnoreNotFound(r.Delete(ctx, legacy))
+}
+
+// userEdited reports whether the spec of a generated AlertRule was changed since it was last rendered
+func userEdited(alertRule *monitoringv1.AlertRule) bool {
+	hash, ok := alertRule.Annotations[monitoringv1.GeneratedSpecHashAnnotation]
+	return ok && hash != specHash(&alertRule.Spec)
+}
+
+// generatedAlertRuleInSync reports whether an existing AlertRule carries the rendered spec,
+// labels and annotations and is controlled by the workload
+func generatedAlertRuleInSync(existing, desired *monitoringv1.AlertRule, workload client.Object) bool {
+	if !metav1.IsControlledBy(existing, workload) || !equality.Semantic.DeepEqual(existing.Spec, desired.Spec) {
+		return false
+	}
+	for k, v := range desired.Labels {
+		if existing.Labels[k] != v {
+			return false
+		}
+	}
+	for k, v := range desired.Annotations {
+		if existing.Annotations[k] != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+// specHash returns a content hash of an AlertRule spec
+func specHash(spec *monitoringv1.AlertRuleSpec) string {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// buildAlertRule builds the AlertRule for a workload from the AlertRuleTemplates selecting it.
+// If no AlertRuleTemplate applies to the kind of the workload the built-in default rule is used,
+// and nil is returned when templates apply to the kind but none of them selects the workload.
+func (r *WorkloadReconciler) buildAlertRule(
+	ctx context.Context, workload client.Object, namespace *corev1.Namespace, name string,
+) (*monitoringv1.AlertRule, error) {
+	logger := log.FromContext(ctx)
+
+	templates := &monitoringv1.AlertRuleTemplateList{}
+	if err := r.List(ctx, templates); err != nil {
+		return nil, fmt.Errorf("unable to list AlertRuleTemplates: %w", err)
+	}
+
+	// 이 kind에 적용되는 AlertRuleTemplate이 하나도 없으면 기본 알림 규칙 사용
+	var applicable []*monitoringv1.AlertRuleTemplate
+	for i := range templates.Items {
+		if templateAppliesToKind(&templates.Items[i], r.Kind) {
+			applicable = append(applicable, &templates.Items[i])
+		}
+	}
+	if len(applicable) == 0 {
+		return r.createDefaultAlertRule(workload, name), nil
+	}
+
+	// 렌더링 결과가 항상 같은 순서가 되도록 이름순으로 정렬
+	sort.Slice(applicable, func(i, j int) bool {
+		return applicable[i].Name < applicable[j].Name
+	})
+
+	var rules []monitoringv1.Rule
+	for _, tmpl := range applicable {
+		matches, err := templateSelects(tmpl, workload.GetLabels(), namespace)
+		if err != nil {
+			logger.Error(err, "unable to evaluate AlertRuleTemplate selectors", "template", tmpl.Name)
+			continue
+		}
+		if !matches {
+			continue
+		}
+
+		// 렌더링 오류는 AlertRuleTemplate controller가 템플릿의 Ready condition에 기록
+		rendered, err := renderTemplateRules(tmpl, workload)
+		if err != nil {
+			logger.Error(err, "unable to render AlertRuleTemplate", "template", tmpl.Name)
+			continue
+		}
+		rules = append(rules, rendered...)
+	}
+
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	alertRule := r.newAlertRule(workload, name)
+	alertRule.Spec.Rules = rules
+
+	return alertRule, nil
+}
+
+// resolveAlertingSettings reads the alerting annotations of a workload and its Namespace.
+// Without an AlertingAnnotation, generation is enabled when the Namespace matches NamespaceSelector.
+func (r *WorkloadReconciler) resolveAlertingSettings(
+	ctx context.Context, workload client.Object, namespace *corev1.Namespace,
+) alertingSettings {
+	logger := log.FromContext(ctx)
+
+	settings := alertingSettings{
+		enabled: r.NamespaceSelector == nil || r.NamespaceSelector.Matches(labels.Set(namespace.Labels)),
+		policy:  r.RulePolicy,
+	}
+	if settings.policy == "" {
+		settings.policy = monitoringv1.RulePolicyPreserve
+	}
+
+	if value, ok := lookupAnnotation(monitoringv1.AlertingAnnotation, workload, namespace); ok {
+		switch value {
+		case monitoringv1.AlertingEnabled:
+			settings.enabled = true
+		case monitoringv1.AlertingDisabled:
+			settings.enabled = false
+		default:
+			logger.Info("Ignoring invalid alerting annotation", "annotation", monitoringv1.AlertingAnnotation, "value", value)
+		}
+	}
+
+	if value, ok := lookupAnnotation(monitoringv1.SeverityAnnotation, workload, namespace); ok {
+		switch value {
+		case "critical", "warning", "info":
+			settings.severity = value
+		default:
+			logger.Info("Ignoring invalid severity annotation", "annotation", monitoringv1.SeverityAnnotation, "value", value)
+		}
+	}
+
+	if value, ok := lookupAnnotation(monitoringv1.ForAnnotation, workload, namespace); ok {
+		if _, err := model.ParseDuration(value); err != nil {
+			logger.Info("Ignoring invalid for annotation", "annotation", monitoringv1.ForAnnotation, "value", value)
+		} else {
+			settings.duration = value
+		}
+	}
+
+	if value, ok := lookupAnnotation(monitoringv1.RulePolicyAnnotation, workload, namespace); ok {
+		switch value {
+		case monitoringv1.RulePolicyOverwrite, monitoringv1.RulePolicyPreserve, monitoringv1.RulePolicyAdopt:
+			settings.policy = value
+		default:
+			logger.Info("Ignoring invalid rule policy annotation", "annotation", monitoringv1.RulePolicyAnnotation, "value", value)
+		}
+	}
+
+	if value, ok := lookupAnnotation(monitoringv1.MaintenanceUntilAnnotation, workload, namespace); ok {
+		until, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			logger.Info("Ignoring invalid maintenance annotation", "annotation", monitoringv1.MaintenanceUntilAnnotation, "value", value)
+		} else {
+			settings.maintenanceUntil = &metav1.Time{Time: until}
+		}
+	}
+
+	return settings
+}
+
+// lookupAnnotation returns the value of an annotation, looking at the objects in order
+func lookupAnnotation(key string, objs ...client.Object) (string, bool) {
+	for _, obj := range objs {
+		if value, ok := obj.GetAnnotations()[key]; ok {
+			return value, true
+		}
+	}
+
+	return "", false
+}
+
+// applyAlertingSettings applies severity and duration overrides to the alerting rules of an AlertRule
+// and adds the maintenance window of the workload
+func applyAlertingSettings(alertRule *monitoringv1.AlertRule, settings alertingSettings) {
+	if settings.maintenanceUntil != nil {
+		alertRule.Spec.Maintenance = append(alertRule.Spec.Maintenance, monitoringv1.MaintenanceWindow{
+			End:     settings.maintenanceUntil,
+			Comment: fmt.Sprintf("%s annotation", monitoringv1.MaintenanceUntilAnnotation),
+		})
+	}
+
+	if settings.severity != "" {
+		alertRule.Spec.Severity = settings.severity
+	}
+	if settings.duration != "" && alertRule.Spec.Expr != "" {
+		alertRule.Spec.For = settings.duration
+	}
+
+	for i := range alertRule.Spec.Rules {
+		rule := &alertRule.Spec.Rules[i]
+		// recording rule에는 severity, for를 적용하지 않음
+		if rule.Alert == "" {
+			continue
+		}
+		if settings.severity != "" {
+			if rule.Labels == nil {
+				rule.Labels = map[string]string{}
+			}
+			rule.Labels["severity"] = settings.severity
+		}
+		if settings.duration != "" {
+			rule.For = settings.duration
+		}
+	}
+}
+
+// createDefaultAlertRule creates the built-in default AlertRule for a workload
+func (r *WorkloadReconciler) createDefaultAlertRule(workload client.Object, name string) *monitoringv1.AlertRule {
+	// 기본 알림 규칙 생성
+	spec := workloadKinds[r.Kind].defaultSpec(workload.GetName(), workload.GetNamespace())
+
+	alertRule := r.newAlertRule(workload, name)
+	alertRule.Spec.Alert = spec.Alert
+	alertRule.Spec.Expr = spec.Expr
+	alertRule.Spec.For = spec.For
+	alertRule.Spec.Severity = spec.Severity
+	alertRule.Spec.Annotations = spec.Annotations
+
+	return alertRule
+}
+
+// newAlertRule creates an AlertRule without rules owned by a workload
+func (r *WorkloadReconciler) newAlertRule(workload client.Object, name string) *monitoringv1.AlertRule {
+	kind := workloadKinds[r.Kind]
+
+	alertRule := &monitoringv1.AlertRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: workload.GetNamespace(),
+			Labels: map[string]string{
+				"app":                       workload.GetName(),
+				"managed-by":                "alert-rule-operator",
+				monitoringv1.GeneratedLabel: "true",
+				fmt.Sprintf("%s.kubernetes.io/name", strings.ToLower(r.Kind)): workload.GetName(),
+			},
+		},
+		Spec: monitoringv1.AlertRuleSpec{
+			Labels: map[string]string{
+				kind.metricLabel: workload.GetName(),
+				"namespace":      workload.GetNamespace(),
+			},
+			WorkloadRef: r.workloadReference(workload),
+		},
+	}
+
+	// Controller reference 설정
+	if err := ctrl.SetControllerReference(workload, alertRule, r.Scheme); err != nil {
+		log.Log.Error(err, "unable to set controller reference")
+	}
+
+	return alertRule
+}
+
+// workloadReference returns the reference to a workload stored in the AlertRule spec
+func (r *WorkloadReconciler) workloadReference(workload client.Object) *monitoringv1.WorkloadReference {
+	return &monitoringv1.WorkloadReference{
+		APIVersion: workloadKinds[r.Kind].apiVersion,
+		Kind:       r.Kind,
+		Name:       workload.GetName(),
+	}
+}
+
+// templateAppliesToKind reports whether an AlertRuleTemplate applies to a workload kind.
+// Templates without kinds apply to Deployments only.
+func templateAppliesToKind(tmpl *monitoringv1.AlertRuleTemplate, kind string) bool {
+	if len(tmpl.Spec.Kinds) == 0 {
+		return kind == "Deployment"
+	}
+
+	return slices.Contains(tmpl.Spec.Kinds, kind)
+}
+
+// deleteAlertRuleForWorkload deletes the AlertRule associated with a workload
+func (r *WorkloadReconciler) deleteAlertRuleForWorkload(ctx context.Context, namespace, workloadName string) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	names := []string{workloadAlertRuleName(r.Kind, workloadName)}
+	if r.Kind == "Deployment" {
+		names = append(names, legacyDeploymentAlertRuleName(workloadName))
+	}
+
+	for _, alertRuleName := range names {
+		alertRule := &monitoringv1.AlertRule{}
+		err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: alertRuleName}, alertRule)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				// AlertRule이 이미 없으면 스킵
+				continue
+			}
+			logger.Error(err, "unable to fetch AlertRule for deletion")
+			return ctrl.Result{}, err
+		}
+
+		// 사용자가 작성했거나 다른 workload에 대해 생성된 AlertRule은 삭제하지 않음
+		if !generatedFor(alertRule, r.Kind, workloadName) {
+			continue
+		}
+
+		logger.Info("Deleting AlertRule for deleted workload", "alertrule", alertRuleName)
+		if err := r.Delete(ctx, alertRule); err != nil {
+			if !apierrors.IsNotFound(err) {
+				logger.Error(err, "unable to delete AlertRule")
+				return ctrl.Result{}, err
+			}
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// listWorkloads lists the workloads of the reconciled kind
+func (r *WorkloadReconciler) listWorkloads(ctx context.Context, opts ...client.ListOption) ([]client.Object, error) {
+	list := workloadKinds[r.Kind].newList()
+	if err := r.List(ctx, list, opts...); err != nil {
+		return nil, err
+	}
+
+	return extractObjects(list)
+}
+
+// extractObjects returns the items of a list as client objects
+func extractObjects(list client.ObjectList) ([]client.Object, error) {
+	items, err := apimeta.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+
+	objs := make([]client.Object, 0, len(items))
+	for _, item := range items {
+		if obj, ok := item.(client.Object); ok {
+			objs = append(objs, obj)
+		}
+	}
+
+	return objs, nil
+}
+
+// workloadsForTemplate maps an AlertRuleTemplate to reconcile requests for every workload of the
+// reconciled kind. The kinds and selectors the template had before the change are not known, so
+// workloads it no longer selects are enqueued as well to drop or re-render their AlertRules.
+func (r *WorkloadReconciler) workloadsForTemplate(ctx context.Context, obj client.Object) []reconcile.Request {
+	logger := log.FromContext(ctx)
+
+	workloads, err := r.listWorkloads(ctx)
+	if err != nil {
+		logger.Error(err, "unable to list workloads for AlertRuleTemplate", "kind", r.Kind, "template", obj.GetName())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(workloads))
+	for _, workload := range workloads {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: workload.GetNamespace(), Name: workload.GetName()},
+		})
+	}
+
+	return requests
+}
+
+// workloadsForNamespace maps a Namespace to reconcile requests for all of its workloads
+func (r *WorkloadReconciler) workloadsForNamespace(ctx context.Context, obj client.Object) []reconcile.Request {
+	logger := log.FromContext(ctx)
+
+	workloads, err := r.listWorkloads(ctx, client.InNamespace(obj.GetName()))
+	if err != nil {
+		logger.Error(err, "unable to list workloads for Namespace", "kind", r.Kind, "namespace", obj.GetName())
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(workloads))
+	for _, workload := range workloads {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: workload.GetNamespace(), Name: workload.GetName()},
+		})
+	}
+
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *WorkloadReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	kind, ok := workloadKinds[r.Kind]
+	if !ok {
+		return fmt.Errorf("unsupported workload kind %q", r.Kind)
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(kind.newObject()).
+		Watches(&monitoringv1.AlertRuleTemplate{}, handler.EnqueueRequestsFromMapFunc(r.workloadsForTemplate)).
+		Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.workloadsForNamespace)).
+		Named(strings.ToLower(r.Kind)).
+		Complete(r)
+}
@@ -0,0 +1,532 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	monitoringv1 "github.com/Kim-Yukyung/k8s-alert-rule-operator/api/v1"
+)
+
+func newTestDeployment(name string, labels map[string]string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+			Labels:    labels,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": name}},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "app", Image: "nginx"}},
+				},
+			},
+		},
+	}
+}
+
+var _ = Describe("Workload Controller", func() {
+	Context("When AlertRuleTemplates exist", func() {
+		var (
+			template   *monitoringv1.AlertRuleTemplate
+			deployment *appsv1.Deployment
+			reconciler *WorkloadReconciler
+		)
+
+		BeforeEach(func() {
+			deployment = nil
+			template = &monitoringv1.AlertRuleTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "frontend-defaults"},
+				Spec: monitoringv1.AlertRuleTemplateSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "frontend"}},
+					Severity: "warning",
+					Rules: []monitoringv1.Rule{
+						{
+							Alert: "{{ .Name }}Unavailable",
+							Expr:  `kube_deployment_status_replicas_unavailable{deployment="{{ .Name }}", namespace="{{ .Namespace }}"} > 0`,
+							For:   "5m",
+							Annotations: map[string]string{
+								"summary": "{{ .Name }} has unavailable replicas",
+							},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, template)).To(Succeed())
+
+			reconciler = &WorkloadReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+				Kind:   "Deployment",
+			}
+		})
+
+		AfterEach(func() {
+			Expect(k8sClient.Delete(ctx, template)).To(Succeed())
+			if deployment != nil {
+				Expect(k8sClient.Delete(ctx, deployment)).To(Succeed())
+				alertRule := &monitoringv1.AlertRule{}
+				key := types.NamespacedName{Namespace: "default", Name: deployment.Name + "-deployment-alert"}
+				if err := k8sClient.Get(ctx, key, alertRule); err == nil {
+					Expect(k8sClient.Delete(ctx, alertRule)).To(Succeed())
+				}
+			}
+		})
+
+		It("should render the selecting template into the AlertRule", func() {
+			deployment = newTestDeployment("web", map[string]string{"tier": "frontend"})
+			Expect(k8sClient.Create(ctx, deployment)).To(Succeed())
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: "default", Name: "web"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			alertRule := &monitoringv1.AlertRule{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "web-deployment-alert"}, alertRule)).To(Succeed())
+			Expect(alertRule.Spec.Expr).To(BeEmpty())
+			Expect(alertRule.Spec.Rules).To(HaveLen(1))
+			Expect(alertRule.Spec.Rules[0].Alert).To(Equal("webUnavailable"))
+			Expect(alertRule.Spec.Rules[0].Expr).To(ContainSubstring(`deployment="web", namespace="default"`))
+			Expect(alertRule.Spec.Rules[0].Labels).To(HaveKeyWithValue("severity", "warning"))
+			Expect(alertRule.Spec.Rules[0].Annotations).To(HaveKeyWithValue("summary", "web has unavailable replicas"))
+		})
+
+		It("should keep the built-in default for kinds no template applies to", func() {
+			statefulSet := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "cache", Namespace: "default"},
+				Spec: appsv1.StatefulSetSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "cache"}},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "cache"}},
+						Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "cache", Image: "redis"}}},
+					},
+				},
+			}
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(k8sClient.Scheme()).
+				WithObjects(template, statefulSet, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}).
+				Build()
+			statefulSetReconciler := &WorkloadReconciler{Client: fakeClient, Scheme: k8sClient.Scheme(), Kind: "StatefulSet"}
+
+			_, err := statefulSetReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: "default", Name: "cache"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			alertRule := &monitoringv1.AlertRule{}
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "cache-statefulset-alert"}, alertRule)).To(Succeed())
+			Expect(alertRule.Spec.Alert).To(Equal("cacheReplicasNotReady"))
+		})
+
+		It("should not create an AlertRule when no template selects the Deployment", func() {
+			deployment = newTestDeployment("worker", map[string]string{"tier": "backend"})
+			Expect(k8sClient.Create(ctx, deployment)).To(Succeed())
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: "default", Name: "worker"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			alertRule := &monitoringv1.AlertRule{}
+			err = k8sClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "worker-deployment-alert"}, alertRule)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("When alerting annotations are set", func() {
+		var deployment *appsv1.Deployment
+
+		AfterEach(func() {
+			Expect(k8sClient.Delete(ctx, deployment)).To(Succeed())
+			alertRule := &monitoringv1.AlertRule{}
+			key := types.NamespacedName{Namespace: "default", Name: deployment.Name + "-deployment-alert"}
+			if err := k8sClient.Get(ctx, key, alertRule); err == nil {
+				Expect(k8sClient.Delete(ctx, alertRule)).To(Succeed())
+			}
+		})
+
+		It("should skip Deployments that opt out", func() {
+			deployment = newTestDeployment("batch", nil)
+			deployment.Annotations = map[string]string{monitoringv1.AlertingAnnotation: monitoringv1.AlertingDisabled}
+			Expect(k8sClient.Create(ctx, deployment)).To(Succeed())
+
+			reconciler := &WorkloadReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), Kind: "Deployment"}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: "default", Name: "batch"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			alertRule := &monitoringv1.AlertRule{}
+			err = k8sClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "batch-deployment-alert"}, alertRule)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should honor opt-in and overrides outside the namespace selector", func() {
+			deployment = newTestDeployment("api", nil)
+			deployment.Annotations = map[string]string{
+				monitoringv1.AlertingAnnotation: monitoringv1.AlertingEnabled,
+				monitoringv1.SeverityAnnotation: "warning",
+				monitoringv1.ForAnnotation:      "10m",
+			}
+			Expect(k8sClient.Create(ctx, deployment)).To(Succeed())
+
+			selector, err := labels.Parse("alerting=enabled")
+			Expect(err).NotTo(HaveOccurred())
+			reconciler := &WorkloadReconciler{
+				Client:            k8sClient,
+				Scheme:            k8sClient.Scheme(),
+				Kind:              "Deployment",
+				NamespaceSelector: selector,
+			}
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: "default", Name: "api"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			alertRule := &monitoringv1.AlertRule{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "api-deployment-alert"}, alertRule)).To(Succeed())
+			Expect(alertRule.Spec.Alert).To(Equal("apiPodDown"))
+			Expect(alertRule.Spec.Severity).To(Equal("warning"))
+			Expect(alertRule.Spec.For).To(Equal("10m"))
+		})
+
+		It("should add a maintenance window until the time of the maintenance annotation", func() {
+			deployment = newTestDeployment("rollout", nil)
+			deployment.Annotations = map[string]string{monitoringv1.MaintenanceUntilAnnotation: "2026-10-16T18:30:00Z"}
+			Expect(k8sClient.Create(ctx, deployment)).To(Succeed())
+
+			reconciler := &WorkloadReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), Kind: "Deployment"}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: "default", Name: "rollout"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			alertRule := &monitoringv1.AlertRule{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "rollout-deployment-alert"}, alertRule)).To(Succeed())
+			Expect(alertRule.Spec.Maintenance).To(HaveLen(1))
+			Expect(alertRule.Spec.Maintenance[0].Start).To(BeNil())
+			Expect(alertRule.Spec.Maintenance[0].End.UTC()).To(Equal(time.Date(2026, 10, 16, 18, 30, 0, 0, time.UTC)))
+		})
+	})
+
+	Context("When reconciling a StatefulSet", func() {
+		It("should generate the built-in StatefulSet alert", func() {
+			statefulSet := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "db",
+					Namespace: "default",
+				},
+				Spec: appsv1.StatefulSetSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "db"}},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{Name: "db", Image: "postgres"}},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, statefulSet)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, statefulSet)).To(Succeed())
+			}()
+
+			reconciler := &WorkloadReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), Kind: "StatefulSet"}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: "default", Name: "db"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			alertRule := &monitoringv1.AlertRule{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "db-statefulset-alert"}, alertRule)).To(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, alertRule)).To(Succeed())
+			}()
+
+			Expect(alertRule.Spec.Expr).To(ContainSubstring("kube_statefulset_status_replicas_ready"))
+			Expect(alertRule.Spec.Labels).To(HaveKeyWithValue("statefulset", "db"))
+			Expect(alertRule.Spec.WorkloadRef).To(Equal(&monitoringv1.WorkloadReference{
+				APIVersion: "apps/v1",
+				Kind:       "StatefulSet",
+				Name:       "db",
+			}))
+			Expect(alertRule.OwnerReferences).To(HaveLen(1))
+			Expect(alertRule.OwnerReferences[0].Kind).To(Equal("StatefulSet"))
+		})
+	})
+
+	Context("When the generated AlertRule already exists", func() {
+		var (
+			ctx        context.Context
+			fakeClient client.Client
+			deployment *appsv1.Deployment
+			reconciler *WorkloadReconciler
+			key        types.NamespacedName
+		)
+
+		reconcileDeployment := func() *monitoringv1.AlertRule {
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: "default", Name: "web"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			alertRule := &monitoringv1.AlertRule{}
+			Expect(fakeClient.Get(ctx, key, alertRule)).To(Succeed())
+			return alertRule
+		}
+
+		setDeploymentAnnotation := func(name, value string) {
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(deployment), deployment)).To(Succeed())
+			if deployment.Annotations == nil {
+				deployment.Annotations = map[string]string{}
+			}
+			deployment.Annotations[name] = value
+			Expect(fakeClient.Update(ctx, deployment)).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			key = types.NamespacedName{Namespace: "default", Name: "web-deployment-alert"}
+			deployment = newTestDeployment("web", nil)
+			deployment.UID = "uid-web"
+			fakeClient = fake.NewClientBuilder().
+				WithScheme(k8sClient.Scheme()).
+				WithObjects(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}, deployment).
+				Build()
+			reconciler = &WorkloadReconciler{Client: fakeClient, Scheme: k8sClient.Scheme(), Kind: "Deployment"}
+		})
+
+		It("should re-render the generated AlertRule when the Deployment changes", func() {
+			alertRule := reconcileDeployment()
+			Expect(alertRule.Labels).To(HaveKeyWithValue(monitoringv1.GeneratedLabel, "true"))
+			Expect(alertRule.Annotations).To(HaveKey(monitoringv1.GeneratedSpecHashAnnotation))
+			Expect(alertRule.Spec.Severity).To(Equal("critical"))
+
+			setDeploymentAnnotation(monitoringv1.SeverityAnnotation, "warning")
+			Expect(reconcileDeployment().Spec.Severity).To(Equal("warning"))
+		})
+
+		It("should preserve user edits unless the policy is overwrite", func() {
+			alertRule := reconcileDeployment()
+			alertRule.Spec.For = "15m"
+			Expect(fakeClient.Update(ctx, alertRule)).To(Succeed())
+
+			setDeploymentAnnotation(monitoringv1.SeverityAnnotation, "warning")
+			alertRule = reconcileDeployment()
+			Expect(alertRule.Spec.For).To(Equal("15m"))
+			Expect(alertRule.Spec.Severity).To(Equal("critical"))
+
+			setDeploymentAnnotation(monitoringv1.RulePolicyAnnotation, monitoringv1.RulePolicyOverwrite)
+			alertRule = reconcileDeployment()
+			Expect(alertRule.Spec.For).To(Equal("1m"))
+			Expect(alertRule.Spec.Severity).To(Equal("warning"))
+		})
+
+		It("should leave a user-authored AlertRule alone unless the policy is adopt", func() {
+			userRule := &monitoringv1.AlertRule{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+				Spec: monitoringv1.AlertRuleSpec{
+					Alert: "WebSlow",
+					Expr:  "web:latency:p99 > 1",
+				},
+			}
+			Expect(fakeClient.Create(ctx, userRule)).To(Succeed())
+
+			alertRule := reconcileDeployment()
+			Expect(alertRule.Spec.Alert).To(Equal("WebSlow"))
+			Expect(alertRule.Spec.WorkloadRef).To(BeNil())
+			Expect(alertRule.OwnerReferences).To(BeEmpty())
+
+			By("not deleting it with the Deployment")
+			Expect(fakeClient.Delete(ctx, deployment)).To(Succeed())
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: "default", Name: "web"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeClient.Get(ctx, key, &monitoringv1.AlertRule{})).To(Succeed())
+
+			By("adopting it with the adopt policy")
+			deployment.ResourceVersion = ""
+			Expect(fakeClient.Create(ctx, deployment)).To(Succeed())
+			reconciler.RulePolicy = monitoringv1.RulePolicyAdopt
+			alertRule = reconcileDeployment()
+			Expect(alertRule.Spec.Alert).To(Equal("webPodDown"))
+			Expect(alertRule.Labels).To(HaveKeyWithValue(monitoringv1.GeneratedLabel, "true"))
+			Expect(metav1.IsControlledBy(alertRule, deployment)).To(BeTrue())
+		})
+	})
+
+	Context("When workloads of different kinds have similar names", func() {
+		It("should only manage the AlertRule generated for the workload itself", func() {
+			ctx := context.Background()
+			deployment := newTestDeployment("foo-statefulset", nil)
+			deployment.UID = "uid-deployment"
+			statefulSet := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default", UID: "uid-statefulset"},
+				Spec: appsv1.StatefulSetSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "foo"}},
+						Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "foo", Image: "redis"}}},
+					},
+				},
+			}
+			// 이전 이름 규칙으로 Deployment에 대해 생성된 AlertRule은 StatefulSet의 AlertRule 이름과 같음
+			legacy := &monitoringv1.AlertRule{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo-statefulset-alert",
+					Namespace: "default",
+					Labels:    map[string]string{monitoringv1.GeneratedLabel: "true"},
+				},
+				Spec: monitoringv1.AlertRuleSpec{
+					Alert:       "FooStatefulsetDown",
+					Expr:        "up == 0",
+					WorkloadRef: &monitoringv1.WorkloadReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "foo-statefulset"},
+				},
+			}
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(k8sClient.Scheme()).
+				WithObjects(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}, deployment, legacy).
+				Build()
+			deployments := &WorkloadReconciler{Client: fakeClient, Scheme: k8sClient.Scheme(), Kind: "Deployment"}
+			statefulSets := &WorkloadReconciler{
+				Client:     fakeClient,
+				Scheme:     k8sClient.Scheme(),
+				Kind:       "StatefulSet",
+				RulePolicy: monitoringv1.RulePolicyOverwrite,
+			}
+			reconcileStatefulSet := func() {
+				_, err := statefulSets.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(statefulSet)})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			By("not deleting it when a StatefulSet of the same AlertRule name is deleted")
+			reconcileStatefulSet()
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(legacy), &monitoringv1.AlertRule{})).To(Succeed())
+
+			By("not overwriting it for a StatefulSet of the same AlertRule name")
+			Expect(fakeClient.Create(ctx, statefulSet)).To(Succeed())
+			reconcileStatefulSet()
+			alertRule := &monitoringv1.AlertRule{}
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(legacy), alertRule)).To(Succeed())
+			Expect(alertRule.Spec.WorkloadRef.Kind).To(Equal("Deployment"))
+
+			By("renaming it to the name including the kind")
+			_, err := deployments.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(deployment)})
+			Expect(err).NotTo(HaveOccurred())
+			renamed := &monitoringv1.AlertRule{}
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "foo-statefulset-deployment-alert"}, renamed)).To(Succeed())
+			Expect(renamed.Spec.WorkloadRef.Name).To(Equal("foo-statefulset"))
+
+			By("generating the AlertRule of the StatefulSet once the name is free")
+			reconcileStatefulSet()
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(legacy), alertRule)).To(Succeed())
+			Expect(alertRule.Spec.WorkloadRef.Kind).To(Equal("StatefulSet"))
+			Expect(alertRule.Spec.WorkloadRef.Name).To(Equal("foo"))
+		})
+	})
+
+	Context("When an AlertRuleTemplate changes", func() {
+		var (
+			ctx        context.Context
+			fakeClient client.Client
+			template   *monitoringv1.AlertRuleTemplate
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			template = &monitoringv1.AlertRuleTemplate{
+				ObjectMeta: metav1.ObjectMeta{Name: "team-defaults", Generation: 1},
+				Spec: monitoringv1.AlertRuleTemplateSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "frontend"}},
+					Rules: []monitoringv1.Rule{{
+						Alert: "{{ .Name }}Down",
+						Expr:  `up{job="{{ .Name }}"} == 0`,
+						Labels: map[string]string{
+							"team": "{{ .Labels.team }}",
+						},
+					}},
+				},
+			}
+			fakeClient = fake.NewClientBuilder().
+				WithScheme(k8sClient.Scheme()).
+				WithObjects(
+					&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+					template,
+					newTestDeployment("web", map[string]string{"tier": "frontend", "team": "web"}),
+					newTestDeployment("admin", map[string]string{"tier": "frontend"}),
+					newTestDeployment("db", map[string]string{"tier": "backend"}),
+				).
+				WithStatusSubresource(template).
+				Build()
+		})
+
+		It("should enqueue the workloads the template no longer selects", func() {
+			reconciler := &WorkloadReconciler{Client: fakeClient, Scheme: k8sClient.Scheme(), Kind: "Deployment"}
+
+			Expect(reconciler.workloadsForTemplate(ctx, template)).To(ConsistOf(
+				reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "web"}},
+				reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "admin"}},
+				reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "db"}},
+			))
+		})
+
+		It("should report the workloads the template cannot be rendered for", func() {
+			reconciler := &AlertRuleTemplateReconciler{Client: fakeClient, Scheme: k8sClient.Scheme()}
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: template.Name}})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(template), template)).To(Succeed())
+			ready := apimeta.FindStatusCondition(template.Status.Conditions, "Ready")
+			Expect(ready).NotTo(BeNil())
+			Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+			Expect(ready.Reason).To(Equal("RenderFailed"))
+			Expect(ready.Message).To(ContainSubstring("1 of 2 selected workloads"))
+			Expect(ready.Message).To(ContainSubstring("Deployment default/admin"))
+
+			By("rendering once the workload has the label")
+			admin := &appsv1.Deployment{}
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Namespace: "default", Name: "admin"}, admin)).To(Succeed())
+			admin.Labels["team"] = "admin"
+			Expect(fakeClient.Update(ctx, admin)).To(Succeed())
+
+			_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: template.Name}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(template), template)).To(Succeed())
+			ready = apimeta.FindStatusCondition(template.Status.Conditions, "Ready")
+			Expect(ready.Status).To(Equal(metav1.ConditionTrue))
+			Expect(ready.Reason).To(Equal("Rendered"))
+		})
+	})
+})
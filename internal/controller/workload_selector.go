@@ -0,0 +1,216 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql/parser"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	monitoringv1 "github.com/Kim-Yukyung/k8s-alert-rule-operator/api/v1"
+)
+
+// updateMatchedWorkloads records in the AlertRule status the workloads selected by its
+// WorkloadSelector, which the rules are scoped to when they are built
+func (r *AlertRuleReconciler) updateMatchedWorkloads(ctx context.Context, alertRule *monitoringv1.AlertRule) error {
+	var matched []string
+	if alertRule.Spec.WorkloadSelector != nil {
+		var err error
+		if matched, err = r.selectedWorkloads(ctx, alertRule.Namespace, alertRule.Spec.WorkloadSelector); err != nil {
+			return err
+		}
+	}
+
+	if slices.Equal(matched, alertRule.Status.MatchedWorkloads) {
+		return nil
+	}
+
+	logf.FromContext(ctx).Info("Selected workloads changed", "alertrule", alertRule.Name, "workloads", matched)
+	alertRule.Status.MatchedWorkloads = matched
+
+	return r.Status().Update(ctx, alertRule)
+}
+
+// selectedWorkloads returns the sorted names of the workloads of a namespace matching a WorkloadSelector
+func (r *AlertRuleReconciler) selectedWorkloads(ctx context.Context, namespace string, selector *monitoringv1.WorkloadSelector) ([]string, error) {
+	kind, ok := workloadKinds[selectorKind(selector)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported workload kind %q", selector.Kind)
+	}
+
+	labelSelector, err := metav1.LabelSelectorAsSelector(&selector.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid workload selector: %w", err)
+	}
+
+	list := kind.newList()
+	if err := r.List(ctx, list, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: labelSelector}); err != nil {
+		return nil, fmt.Errorf("unable to list %ss: %w", selectorKind(selector), err)
+	}
+	items, err := extractObjects(list)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, workload := range items {
+		// 삭제 중이거나 다른 workload의 AlertRule로 감시되는 workload는 제외
+		if !workload.GetDeletionTimestamp().IsZero() || (kind.ignore != nil && kind.ignore(workload)) {
+			continue
+		}
+		names = append(names, workload.GetName())
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// alertRulesSelecting returns a map function enqueuing the AlertRules of a workload's namespace
+// that select workloads of the given kind or reference the workload, so they follow workloads
+// as they come and go and re-render templates using the workload's labels and annotations
+func (r *AlertRuleReconciler) alertRulesSelecting(kind string) func(context.Context, client.Object) []reconcile.Request {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		alertRules := &monitoringv1.AlertRuleList{}
+		if err := r.List(ctx, alertRules, client.InNamespace(obj.GetNamespace())); err != nil {
+			logf.FromContext(ctx).Error(err, "unable to list AlertRules")
+			return nil
+		}
+
+		// workload의 이전 label은 알 수 없으므로 같은 kind를 선택하는 AlertRule을 모두 큐에 넣음
+		var requests []reconcile.Request
+		for _, alertRule := range alertRules.Items {
+			selector := alertRule.Spec.WorkloadSelector
+			ref := alertRule.Spec.WorkloadRef
+			selecting := selector != nil && selectorKind(selector) == kind
+			referencing := ref != nil && ref.Kind == kind && ref.Name == obj.GetName() && hasTemplates(&alertRule.Spec)
+			if !selecting && !referencing {
+				continue
+			}
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: alertRule.Namespace, Name: alertRule.Name},
+			})
+		}
+
+		return requests
+	}
+}
+
+// targetRules returns the rules of an AlertRule scoped to the workloads recorded in its status.
+// Without a WorkloadSelector the rules are returned as declared, and no rule is returned
+// while the selector matches no workload.
+func targetRules(alertRule *monitoringv1.AlertRule) []monitoringv1.Rule {
+	rules := specRules(&alertRule.Spec)
+
+	selector := alertRule.Spec.WorkloadSelector
+	if selector == nil {
+		return rules
+	}
+
+	workloads := alertRule.Status.MatchedWorkloads
+	if len(workloads) == 0 {
+		return nil
+	}
+
+	label := selectorLabel(selector)
+	scoped := []monitoringv1.Rule{}
+	for _, rule := range rules {
+		if selector.Mode == monitoringv1.WorkloadSelectorModeExpand {
+			// workload마다 rule을 복제하고 workload label을 붙임
+			for _, workload := range workloads {
+				expanded, err := scopeRule(rule, labels.MustNewMatcher(labels.MatchEqual, label, workload))
+				if err != nil {
+					logf.Log.Error(err, "unable to scope rule to workload", "alertrule", alertRule.Name, "workload", workload)
+					continue
+				}
+				expanded.Labels = copyLabels(rule.Labels)
+				expanded.Labels[label] = workload
+				scoped = append(scoped, expanded)
+			}
+			continue
+		}
+
+		quoted := make([]string, 0, len(workloads))
+		for _, workload := range workloads {
+			quoted = append(quoted, regexp.QuoteMeta(workload))
+		}
+		matched, err := scopeRule(rule, labels.MustNewMatcher(labels.MatchRegexp, label, strings.Join(quoted, "|")))
+		if err != nil {
+			logf.Log.Error(err, "unable to scope rule to workloads", "alertrule", alertRule.Name)
+			continue
+		}
+		scoped = append(scoped, matched)
+	}
+
+	return scoped
+}
+
+// scopeRule returns a copy of a rule with the matcher added to every vector selector of its expression
+func scopeRule(rule monitoringv1.Rule, matcher *labels.Matcher) (monitoringv1.Rule, error) {
+	expr, err := parser.ParseExpr(rule.Expr)
+	if err != nil {
+		return rule, err
+	}
+
+	parser.Inspect(expr, func(node parser.Node, _ []parser.Node) error {
+		if vs, ok := node.(*parser.VectorSelector); ok {
+			vs.LabelMatchers = append(vs.LabelMatchers, matcher)
+		}
+		return nil
+	})
+	rule.Expr = expr.String()
+
+	return rule, nil
+}
+
+// copyLabels returns a copy of a label map that is never nil
+func copyLabels(in map[string]string) map[string]string {
+	out := make(map[string]string, len(in)+1)
+	for k, v := range in {
+		out[k] = v
+	}
+
+	return out
+}
+
+// selectorKind returns the workload kind selected by a WorkloadSelector
+func selectorKind(selector *monitoringv1.WorkloadSelector) string {
+	if selector.Kind == "" {
+		return "Deployment"
+	}
+
+	return selector.Kind
+}
+
+// selectorLabel returns the metric label carrying the name of the workloads selected by a WorkloadSelector
+func selectorLabel(selector *monitoringv1.WorkloadSelector) string {
+	if selector.Label != "" {
+		return selector.Label
+	}
+
+	return workloadKinds[selectorKind(selector)].metricLabel
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ruletemplate finds the parts of the Go templates in the fields of an AlertRule that
+// reference its variables (.Name, .Namespace and .Workload), which are rendered by the operator,
+// and leaves every other part for Prometheus to evaluate.
+package ruletemplate
+
+import (
+	"fmt"
+	"strings"
+	"text/template/parse"
+)
+
+// variables are the fields of the data the operator renders templates with.
+// Prometheus template data has no such fields.
+var variables = map[string]bool{"Name": true, "Namespace": true, "Workload": true}
+
+// prometheusDefs declares the variables Prometheus defines before evaluating a template,
+// so that actions such as {{ $labels.pod }} parse
+const prometheusDefs = "{{$labels := .Labels}}{{$externalLabels := .ExternalLabels}}" +
+	"{{$externalURL := .ExternalURL}}{{$value := .Value}}"
+
+const (
+	leftDelim  = "{{"
+	rightDelim = "}}"
+)
+
+// HasVariables reports whether a field value references a variable of an AlertRule.
+// Values that are not valid templates are reported as well, so that the parse error is surfaced.
+func HasVariables(text string) bool {
+	if !strings.Contains(text, leftDelim) {
+		return false
+	}
+	actions, err := VariableActions(text)
+
+	return err != nil || len(actions) > 0
+}
+
+// VariableActions parses a field value and returns the byte ranges, in the form of
+// regexp.FindAllStringIndex, of its top-level actions and control structures referencing a
+// variable of an AlertRule anywhere inside them, e.g. {{ .Workload.Name }},
+// {{ printf "%s" .Namespace }} or {{ if .Workload }}...{{ end }}.
+// Functions are not checked, since Prometheus templates use functions such as humanize or query.
+func VariableActions(text string) ([][]int, error) {
+	if !strings.Contains(text, leftDelim) {
+		return nil, nil
+	}
+
+	full := prometheusDefs + text
+	tree := parse.New("template")
+	tree.Mode = parse.SkipFuncCheck
+	if _, err := tree.Parse(full, leftDelim, rightDelim, map[string]*parse.Tree{}); err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+
+	// Prometheus 변수 선언은 건너뛰고 최상위 노드의 시작 위치를 구함
+	var nodes []parse.Node
+	var starts []int
+	for _, node := range tree.Root.Nodes {
+		pos := int(node.Position())
+		if pos < len(prometheusDefs) {
+			continue
+		}
+		if node.Type() != parse.NodeText {
+			pos = strings.LastIndex(full[:pos], leftDelim)
+		}
+		nodes = append(nodes, node)
+		starts = append(starts, pos)
+	}
+
+	var actions [][]int
+	for i, node := range nodes {
+		if node.Type() == parse.NodeText || !references(node, true) {
+			continue
+		}
+
+		// 다음 노드 직전의 }}까지가 이 노드이며 사이에는 공백이나 주석만 있음
+		next := len(full)
+		if i+1 < len(nodes) {
+			next = starts[i+1]
+		}
+		end := strings.LastIndex(full[:next], rightDelim) + len(rightDelim)
+		actions = append(actions, []int{starts[i] - len(prometheusDefs), end - len(prometheusDefs)})
+	}
+
+	return actions, nil
+}
+
+// references reports whether a node references a variable of an AlertRule. Fields are only
+// variables while dot is the template data, which with and range bodies rebind.
+func references(node parse.Node, dot bool) bool {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return false
+		}
+		for _, child := range n.Nodes {
+			if references(child, dot) {
+				return true
+			}
+		}
+	case *parse.ActionNode:
+		return references(n.Pipe, dot)
+	case *parse.TemplateNode:
+		return references(n.Pipe, dot)
+	case *parse.IfNode:
+		return references(n.Pipe, dot) || references(n.List, dot) || references(n.ElseList, dot)
+	case *parse.WithNode:
+		return references(n.Pipe, dot) || references(n.List, false) || references(n.ElseList, dot)
+	case *parse.RangeNode:
+		return references(n.Pipe, dot) || references(n.List, false) || references(n.ElseList, dot)
+	case *parse.PipeNode:
+		if n == nil {
+			return false
+		}
+		for _, cmd := range n.Cmds {
+			if references(cmd, dot) {
+				return true
+			}
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			if references(arg, dot) {
+				return true
+			}
+		}
+	case *parse.ChainNode:
+		return references(n.Node, dot)
+	case *parse.FieldNode:
+		return dot && variables[n.Ident[0]]
+	case *parse.VariableNode:
+		// $는 with, range 안에서도 템플릿 데이터를 가리킴
+		return len(n.Ident) > 1 && n.Ident[0] == "$" && variables[n.Ident[1]]
+	}
+
+	return false
+}
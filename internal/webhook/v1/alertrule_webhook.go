@@ -0,0 +1,370 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/promql/parser"
+	"github.com/robfig/cron/v3"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	monitoringv1 "github.com/Kim-Yukyung/k8s-alert-rule-operator/api/v1"
+	"github.com/Kim-Yukyung/k8s-alert-rule-operator/internal/ruletemplate"
+)
+
+// log is for logging in this package.
+var alertrulelog = logf.Log.WithName("alertrule-resource")
+
+// SetupAlertRuleWebhookWithManager registers the webhook for AlertRule in the manager.
+func SetupAlertRuleWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&monitoringv1.AlertRule{}).
+		WithValidator(&AlertRuleCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-monitoring-example-com-v1-alertrule,mutating=false,failurePolicy=fail,sideEffects=None,groups=monitoring.example.com,resources=alertrules,verbs=create;update,versions=v1,name=valertrule-v1.kb.io,admissionReviewVersions=v1
+
+// AlertRuleCustomValidator validates the AlertRule resource when it is created or updated.
+// It parses the PromQL expression and the "for" duration so that broken rules are rejected
+// before they ever reach Prometheus.
+type AlertRuleCustomValidator struct{}
+
+var _ webhook.CustomValidator = &AlertRuleCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type AlertRule.
+func (v *AlertRuleCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	alertrule, ok := obj.(*monitoringv1.AlertRule)
+	if !ok {
+		return nil, fmt.Errorf("expected a AlertRule object but got %T", obj)
+	}
+	alertrulelog.Info("Validation for AlertRule upon creation", "name", alertrule.GetName())
+
+	return nil, validateAlertRule(alertrule)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type AlertRule.
+// Updates of an AlertRule being deleted or leaving its spec unchanged, such as removing the finalizer,
+// are not validated, so AlertRules accepted under older validation rules can still be deleted.
+func (v *AlertRuleCustomValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	alertrule, ok := newObj.(*monitoringv1.AlertRule)
+	if !ok {
+		return nil, fmt.Errorf("expected a AlertRule object for the newObj but got %T", newObj)
+	}
+	oldAlertrule, ok := oldObj.(*monitoringv1.AlertRule)
+	if !ok {
+		return nil, fmt.Errorf("expected a AlertRule object for the oldObj but got %T", oldObj)
+	}
+	alertrulelog.Info("Validation for AlertRule upon update", "name", alertrule.GetName())
+
+	if !alertrule.DeletionTimestamp.IsZero() || equality.Semantic.DeepEqual(oldAlertrule.Spec, alertrule.Spec) {
+		return nil, nil
+	}
+
+	return nil, validateAlertRule(alertrule)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type AlertRule.
+func (v *AlertRuleCustomValidator) ValidateDelete(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	// 삭제 시에는 검증할 내용이 없음
+	return nil, nil
+}
+
+// validateAlertRule validates the spec of an AlertRule and returns an Invalid error
+// aggregating every problem found.
+func validateAlertRule(alertrule *monitoringv1.AlertRule) error {
+	allErrs := validateAlertRuleSpec(&alertrule.Spec, field.NewPath("spec"))
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(
+		schema.GroupKind{Group: monitoringv1.GroupVersion.Group, Kind: "AlertRule"},
+		alertrule.Name, allErrs)
+}
+
+// validateAlertRuleSpec validates the single-rule fields and the rules list of an AlertRuleSpec
+func validateAlertRuleSpec(spec *monitoringv1.AlertRuleSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	// 단일 rule 필드와 rules 목록 중 하나는 반드시 있어야 함
+	if spec.Alert != "" || spec.Expr != "" || len(spec.Rules) == 0 {
+		rule := monitoringv1.Rule{Alert: spec.Alert, Expr: spec.Expr, For: spec.For}
+		allErrs = append(allErrs, validateRule(&rule, fldPath)...)
+	}
+
+	for i := range spec.Rules {
+		allErrs = append(allErrs, validateRule(&spec.Rules[i], fldPath.Child("rules").Index(i))...)
+	}
+
+	if spec.GroupSettings != nil {
+		allErrs = append(allErrs, validateGroupSettings(spec.GroupSettings, fldPath.Child("groupSettings"))...)
+	}
+
+	if spec.WorkloadSelector != nil {
+		allErrs = append(allErrs, validateWorkloadSelector(spec.WorkloadSelector, fldPath.Child("workloadSelector"))...)
+	}
+
+	for i := range spec.Tests {
+		allErrs = append(allErrs, validateRuleTest(&spec.Tests[i], fldPath.Child("tests").Index(i))...)
+	}
+
+	if spec.Routing != nil {
+		allErrs = append(allErrs, validateRouting(spec.Routing, fldPath.Child("routing"))...)
+	}
+
+	for i := range spec.Maintenance {
+		allErrs = append(allErrs, validateMaintenanceWindow(&spec.Maintenance[i], fldPath.Child("maintenance").Index(i))...)
+	}
+
+	return allErrs
+}
+
+// validateMaintenanceWindow validates that a maintenance window is either a recurring window with a
+// valid schedule, time zone and duration or a one-off window ending after it starts
+func validateMaintenanceWindow(window *monitoringv1.MaintenanceWindow, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	// 일회성 유지보수 시간
+	if window.Schedule == "" {
+		if window.End == nil {
+			allErrs = append(allErrs, field.Required(fldPath.Child("end"), "end or schedule must be set"))
+		} else if window.Start != nil && !window.End.After(window.Start.Time) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("end"), window.End, "must be after start"))
+		}
+		if window.Duration != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("duration"), "only applies to a schedule"))
+		}
+		if window.TimeZone != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("timeZone"), "only applies to a schedule"))
+		}
+		return allErrs
+	}
+
+	// 반복되는 유지보수 시간
+	if _, err := cron.ParseStandard(window.Schedule); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("schedule"), window.Schedule, err.Error()))
+	}
+	if window.TimeZone != "" {
+		if _, err := time.LoadLocation(window.TimeZone); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("timeZone"), window.TimeZone, err.Error()))
+		}
+	}
+	if window.Duration == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("duration"), "duration of the recurring window must be set"))
+	} else if d, err := model.ParseDuration(window.Duration); err != nil || d <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("duration"), window.Duration, "must be a positive duration"))
+	}
+	if window.Start != nil || window.End != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "start and end cannot be combined with a schedule"))
+	}
+
+	return allErrs
+}
+
+// validateRouting validates the receiver, grouping, repeat interval and matchers of a routing
+func validateRouting(routing *monitoringv1.Routing, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if routing.Receiver == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("receiver"), "receiver must be set"))
+	}
+
+	for i, label := range routing.GroupBy {
+		// "..."은 모든 label로 그룹화
+		if label != "..." && !model.LabelName(label).IsValidLegacy() {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("groupBy").Index(i), label, "must be a valid label name"))
+		}
+	}
+
+	if routing.RepeatInterval != "" {
+		if err := validateDuration(routing.RepeatInterval); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("repeatInterval"), routing.RepeatInterval, err.Error()))
+		}
+	}
+
+	for i, matcher := range routing.Matchers {
+		path := fldPath.Child("matchers").Index(i)
+		if !model.LabelName(matcher.Name).IsValidLegacy() {
+			allErrs = append(allErrs, field.Invalid(path.Child("name"), matcher.Name, "must be a valid label name"))
+		}
+		switch matcher.MatchType {
+		case "", "=", "!=":
+		case "=~", "!~":
+			if _, err := regexp.Compile("^(?:" + matcher.Value + ")$"); err != nil {
+				allErrs = append(allErrs, field.Invalid(path.Child("value"), matcher.Value, err.Error()))
+			}
+		default:
+			allErrs = append(allErrs, field.NotSupported(path.Child("matchType"), matcher.MatchType, []string{"=", "!=", "=~", "!~"}))
+		}
+	}
+
+	return allErrs
+}
+
+// validateRuleTest validates the interval, input series and alert expectations of a rule test
+func validateRuleTest(test *monitoringv1.RuleTest, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if test.Interval != "" {
+		if err := validateDuration(test.Interval); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("interval"), test.Interval, err.Error()))
+		}
+	}
+
+	for i, input := range test.InputSeries {
+		if _, _, err := parser.ParseSeriesDesc(input.Series + " " + input.Values); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("inputSeries").Index(i), input.Series, err.Error()))
+		}
+	}
+
+	for i, alertTest := range test.AlertRuleTests {
+		path := fldPath.Child("alertRuleTests").Index(i)
+		if err := validateDuration(alertTest.EvalTime); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("evalTime"), alertTest.EvalTime, err.Error()))
+		} else if evalTime, _ := model.ParseDuration(alertTest.EvalTime); time.Duration(evalTime) > monitoringv1.MaxTestEvalTime {
+			// 평가 횟수가 eval time에 비례하므로 controller가 실행할 수 있는 범위로 제한
+			allErrs = append(allErrs, field.Invalid(path.Child("evalTime"), alertTest.EvalTime,
+				fmt.Sprintf("must not exceed %s", model.Duration(monitoringv1.MaxTestEvalTime))))
+		}
+		if alertTest.Alertname == "" {
+			allErrs = append(allErrs, field.Required(path.Child("alertname"), "alertname must be set"))
+		}
+	}
+
+	return allErrs
+}
+
+// validateWorkloadSelector validates the label selector and the workload label of a WorkloadSelector
+func validateWorkloadSelector(selector *monitoringv1.WorkloadSelector, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if _, err := metav1.LabelSelectorAsSelector(&selector.Selector); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("selector"), selector.Selector, err.Error()))
+	}
+	if selector.Label != "" && !model.LabelName(selector.Label).IsValidLegacy() {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("label"), selector.Label, "must be a valid label name"))
+	}
+
+	return allErrs
+}
+
+// validateGroupSettings validates the durations of the rule group settings
+func validateGroupSettings(settings *monitoringv1.RuleGroupSettings, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if settings.Interval != "" {
+		if err := validateDuration(settings.Interval); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("interval"), settings.Interval, err.Error()))
+		}
+	}
+	if settings.QueryOffset != "" {
+		if err := validateDuration(settings.QueryOffset); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("queryOffset"), settings.QueryOffset, err.Error()))
+		}
+	}
+
+	return allErrs
+}
+
+// validateRule validates the name, expression and duration of a single rule
+func validateRule(rule *monitoringv1.Rule, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	switch {
+	case rule.Alert == "" && rule.Record == "":
+		allErrs = append(allErrs, field.Required(fldPath.Child("alert"), "either alert or record must be set"))
+	case rule.Alert != "" && rule.Record != "":
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("record"), "alert and record are mutually exclusive"))
+	case rule.Record != "":
+		allErrs = append(allErrs, validateRecordingRule(rule, fldPath)...)
+	}
+
+	// 템플릿이 있는 필드는 렌더링 후 controller가 검증하므로 템플릿 문법만 확인
+	if ruletemplate.HasVariables(rule.Expr) {
+		if _, err := ruletemplate.VariableActions(rule.Expr); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("expr"), rule.Expr, err.Error()))
+		}
+	} else if err := validateExpr(rule.Expr); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("expr"), rule.Expr, err.Error()))
+	}
+
+	if ruletemplate.HasVariables(rule.For) {
+		if _, err := ruletemplate.VariableActions(rule.For); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("for"), rule.For, err.Error()))
+		}
+	} else if rule.For != "" {
+		if err := validateDuration(rule.For); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("for"), rule.For, err.Error()))
+		}
+	}
+
+	return allErrs
+}
+
+// validateRecordingRule validates the fields specific to a recording rule
+func validateRecordingRule(rule *monitoringv1.Rule, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if !model.IsValidLegacyMetricName(rule.Record) {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("record"), rule.Record, "must be a valid metric name"))
+	}
+	if rule.For != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("for"), "recording rules do not support for"))
+	}
+	if len(rule.Annotations) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("annotations"), "recording rules do not support annotations"))
+	}
+
+	return allErrs
+}
+
+// validateExpr parses a PromQL expression. Syntax errors and unknown functions are
+// reported by the parser together with their line:column position in the expression.
+func validateExpr(expr string) error {
+	if expr == "" {
+		return fmt.Errorf("expression must not be empty")
+	}
+
+	if _, err := parser.ParseExpr(expr); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateDuration checks that a value is a valid Prometheus duration (e.g. 30s, 5m, 1h30m)
+func validateDuration(duration string) error {
+	if _, err := model.ParseDuration(duration); err != nil {
+		return fmt.Errorf("invalid duration: %w", err)
+	}
+
+	return nil
+}
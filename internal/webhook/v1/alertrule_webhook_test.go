@@ -0,0 +1,289 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	monitoringv1 "github.com/Kim-Yukyung/k8s-alert-rule-operator/api/v1"
+)
+
+var _ = Describe("AlertRule Webhook", func() {
+	var (
+		obj       *monitoringv1.AlertRule
+		oldObj    *monitoringv1.AlertRule
+		validator AlertRuleCustomValidator
+	)
+
+	BeforeEach(func() {
+		obj = &monitoringv1.AlertRule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-alertrule",
+				Namespace: "default",
+			},
+			Spec: monitoringv1.AlertRuleSpec{
+				Alert: "HighErrorRate",
+				Expr:  `sum(rate(http_requests_total{code=~"5.."}[5m])) > 0.1`,
+				For:   "5m",
+			},
+		}
+		oldObj = obj.DeepCopy()
+		validator = AlertRuleCustomValidator{}
+	})
+
+	Context("When creating or updating AlertRule under Validating Webhook", func() {
+		It("Should admit creation if the expression and duration are valid", func() {
+			By("simulating a valid creation scenario")
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+		})
+
+		It("Should deny creation if the expression has a syntax error", func() {
+			By("simulating an expression with an unbalanced parenthesis")
+			obj.Spec.Expr = "sum(rate(http_requests_total[5m]) > 0.1"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.expr"))
+			Expect(err.Error()).To(ContainSubstring("parse error"))
+		})
+
+		It("Should report the position of the error in the expression", func() {
+			obj.Spec.Expr = "up == == 1"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("1:7"))
+		})
+
+		It("Should deny creation if the expression uses an unknown function", func() {
+			obj.Spec.Expr = "rat(http_requests_total[5m]) > 0"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("unknown function"))
+		})
+
+		It("Should deny creation if the expression is empty", func() {
+			obj.Spec.Expr = ""
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should deny creation if the for duration is malformed", func() {
+			obj.Spec.For = "5 minutes"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.for"))
+		})
+
+		It("Should admit creation if only the rules list is set", func() {
+			obj.Spec.Alert = ""
+			obj.Spec.Expr = ""
+			obj.Spec.For = ""
+			obj.Spec.Rules = []monitoringv1.Rule{
+				{Alert: "ServiceDown", Expr: `up{job="api"} == 0`, For: "1m"},
+				{Alert: "HighLatency", Expr: `histogram_quantile(0.99, rate(http_request_duration_seconds_bucket[5m])) > 1`},
+			}
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+		})
+
+		It("Should deny creation if an entry of the rules list is invalid", func() {
+			obj.Spec.Rules = []monitoringv1.Rule{
+				{Alert: "ServiceDown", Expr: `up{job="api"} == 0`},
+				{Expr: "rate(http_requests_total[5m]", For: "5x"},
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.rules[1].alert"))
+			Expect(err.Error()).To(ContainSubstring("spec.rules[1].expr"))
+			Expect(err.Error()).To(ContainSubstring("spec.rules[1].for"))
+		})
+
+		It("Should admit recording rules referenced by alerting rules", func() {
+			obj.Spec.Rules = []monitoringv1.Rule{
+				{Record: "job:http_errors:ratio_rate5m", Expr: `sum by (job) (rate(http_requests_total{code=~"5.."}[5m])) / sum by (job) (rate(http_requests_total[5m]))`},
+				{Alert: "HighErrorRatio", Expr: "job:http_errors:ratio_rate5m > 0.05", For: "10m"},
+			}
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+		})
+
+		It("Should deny recording rules with alert-only fields or an invalid name", func() {
+			obj.Spec.Rules = []monitoringv1.Rule{
+				{
+					Record:      "job:errors rate",
+					Expr:        "sum(rate(http_requests_total[5m]))",
+					For:         "5m",
+					Annotations: map[string]string{"summary": "not allowed"},
+				},
+				{Alert: "Both", Record: "both", Expr: "up"},
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.rules[0].record"))
+			Expect(err.Error()).To(ContainSubstring("spec.rules[0].for"))
+			Expect(err.Error()).To(ContainSubstring("spec.rules[0].annotations"))
+			Expect(err.Error()).To(ContainSubstring("spec.rules[1].record"))
+		})
+
+		It("Should deny creation if a group setting duration is malformed", func() {
+			obj.Spec.GroupSettings = &monitoringv1.RuleGroupSettings{
+				Interval:    "30s",
+				QueryOffset: "one minute",
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.groupSettings.queryOffset"))
+			Expect(err.Error()).NotTo(ContainSubstring("spec.groupSettings.interval"))
+		})
+
+		It("Should deny creation if the workload selector is invalid", func() {
+			obj.Spec.WorkloadSelector = &monitoringv1.WorkloadSelector{
+				Selector: metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "tier", Operator: "Like", Values: []string{"frontend"}},
+				}},
+				Label: "deployment-name",
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.workloadSelector.selector"))
+			Expect(err.Error()).To(ContainSubstring("spec.workloadSelector.label"))
+		})
+
+		It("Should check only the template syntax of templated fields", func() {
+			obj.Spec.Expr = `kube_deployment_status_replicas_available{deployment="{{ .Workload.Name }}"} == 0`
+			obj.Spec.For = "{{ .Workload.Annotations.for }}"
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+
+			obj.Spec.Expr = `up{namespace="{{ printf "%s" .Namespace }}"{{ if .Workload }}, job="{{ .Workload.Name }}"{{ end }}} == 0`
+			obj.Spec.For = `{{ or (index .Workload.Annotations "for") "5m" }}`
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+
+			obj.Spec.Expr = `up{job="{{ if .Workload }}{{ .Workload.Name }}"} == 0`
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("invalid template"))
+
+			obj.Spec.Expr = `up{job="{{ .Workload.Name }"} == 0`
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("invalid template"))
+		})
+
+		It("Should deny creation if a rule test is malformed", func() {
+			obj.Spec.Tests = []monitoringv1.RuleTest{{
+				Interval: "1m",
+				InputSeries: []monitoringv1.TestSeries{
+					{Series: `up{job="api"}`, Values: "1 0x5"},
+					{Series: `up{job="api"`, Values: "1 1"},
+				},
+				AlertRuleTests: []monitoringv1.AlertRuleTest{{EvalTime: "five minutes"}},
+			}}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.tests[0].inputSeries[1]"))
+			Expect(err.Error()).NotTo(ContainSubstring("spec.tests[0].inputSeries[0]"))
+			Expect(err.Error()).To(ContainSubstring("spec.tests[0].alertRuleTests[0].evalTime"))
+			Expect(err.Error()).To(ContainSubstring("spec.tests[0].alertRuleTests[0].alertname"))
+		})
+
+		It("Should deny creation if a rule test is evaluated for too long", func() {
+			obj.Spec.Tests = []monitoringv1.RuleTest{{
+				InputSeries:    []monitoringv1.TestSeries{{Series: `up{job="api"}`, Values: "1 0x5"}},
+				AlertRuleTests: []monitoringv1.AlertRuleTest{{EvalTime: "1y", Alertname: "HighErrorRate"}},
+			}}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.tests[0].alertRuleTests[0].evalTime"))
+			Expect(err.Error()).To(ContainSubstring("must not exceed 6h"))
+
+			obj.Spec.Tests[0].AlertRuleTests[0].EvalTime = "6h"
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+		})
+
+		It("Should deny creation if the routing is invalid", func() {
+			obj.Spec.Routing = &monitoringv1.Routing{
+				Receiver:       "team-payments",
+				GroupBy:        []string{"alertname", "..."},
+				RepeatInterval: "every 4 hours",
+				Matchers: []monitoringv1.RouteMatcher{
+					{Name: "severity", Value: "critical"},
+					{Name: "instance", Value: "api-(", MatchType: "=~"},
+				},
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.routing.repeatInterval"))
+			Expect(err.Error()).To(ContainSubstring("spec.routing.matchers[1].value"))
+			Expect(err.Error()).NotTo(ContainSubstring("spec.routing.groupBy"))
+			Expect(err.Error()).NotTo(ContainSubstring("spec.routing.matchers[0]"))
+
+			obj.Spec.Routing.RepeatInterval = "4h"
+			obj.Spec.Routing.Matchers[1].Value = "api-.*"
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+		})
+
+		It("Should deny creation if a maintenance window is invalid", func() {
+			start := metav1.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+			end := metav1.Date(2026, 10, 1, 11, 0, 0, 0, time.UTC)
+			obj.Spec.Maintenance = []monitoringv1.MaintenanceWindow{
+				{Schedule: "0 2 * * 6", Duration: "2h", TimeZone: "Europe/Berlin"},
+				{Schedule: "0 25 * * *", TimeZone: "Mars/Olympus"},
+				{Start: &start, End: &end, Duration: "1h"},
+				{Comment: "no end"},
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).NotTo(ContainSubstring("spec.maintenance[0]"))
+			Expect(err.Error()).To(ContainSubstring("spec.maintenance[1].schedule"))
+			Expect(err.Error()).To(ContainSubstring("spec.maintenance[1].timeZone"))
+			Expect(err.Error()).To(ContainSubstring("spec.maintenance[1].duration"))
+			Expect(err.Error()).To(ContainSubstring("spec.maintenance[2].end"))
+			Expect(err.Error()).To(ContainSubstring("spec.maintenance[2].duration"))
+			Expect(err.Error()).To(ContainSubstring("spec.maintenance[3].end"))
+
+			obj.Spec.Maintenance = obj.Spec.Maintenance[:1]
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+		})
+
+		It("Should validate updates correctly", func() {
+			By("simulating an update that breaks the expression")
+			obj.Spec.Expr = "up =="
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(HaveOccurred())
+
+			By("simulating a valid update scenario")
+			obj.Spec.Expr = "up == 0"
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).To(BeNil())
+		})
+
+		It("Should not validate updates leaving the spec unchanged or of deleted AlertRules", func() {
+			By("simulating the removal of the finalizer of an AlertRule that no longer validates")
+			oldObj.Spec.Expr = "up =="
+			oldObj.Finalizers = []string{"monitoring.example.com/cleanup"}
+			obj = oldObj.DeepCopy()
+			obj.Finalizers = nil
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).To(BeNil())
+
+			By("simulating a spec change of an AlertRule being deleted")
+			now := metav1.Now()
+			obj.DeletionTimestamp = &now
+			obj.Spec.For = "10m"
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).To(BeNil())
+		})
+	})
+})
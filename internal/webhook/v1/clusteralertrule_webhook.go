@@ -0,0 +1,130 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	monitoringv1 "github.com/Kim-Yukyung/k8s-alert-rule-operator/api/v1"
+)
+
+// log is for logging in this package.
+var clusteralertrulelog = logf.Log.WithName("clusteralertrule-resource")
+
+// SetupClusterAlertRuleWebhookWithManager registers the webhook for ClusterAlertRule in the manager.
+func SetupClusterAlertRuleWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&monitoringv1.ClusterAlertRule{}).
+		WithValidator(&ClusterAlertRuleCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-monitoring-example-com-v1-clusteralertrule,mutating=false,failurePolicy=fail,sideEffects=None,groups=monitoring.example.com,resources=clusteralertrules,verbs=create;update,versions=v1,name=vclusteralertrule-v1.kb.io,admissionReviewVersions=v1
+
+// ClusterAlertRuleCustomValidator validates the ClusterAlertRule resource when it is created or updated.
+// The rules are checked like those of an AlertRule, and the fields that do not apply to cluster-wide
+// rules are rejected instead of being silently ignored.
+type ClusterAlertRuleCustomValidator struct{}
+
+var _ webhook.CustomValidator = &ClusterAlertRuleCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type ClusterAlertRule.
+func (v *ClusterAlertRuleCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	clusteralertrule, ok := obj.(*monitoringv1.ClusterAlertRule)
+	if !ok {
+		return nil, fmt.Errorf("expected a ClusterAlertRule object but got %T", obj)
+	}
+	clusteralertrulelog.Info("Validation for ClusterAlertRule upon creation", "name", clusteralertrule.GetName())
+
+	return nil, validateClusterAlertRule(clusteralertrule)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type ClusterAlertRule.
+// Like for AlertRules, updates of a ClusterAlertRule being deleted or leaving its spec unchanged are not validated.
+func (v *ClusterAlertRuleCustomValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	clusteralertrule, ok := newObj.(*monitoringv1.ClusterAlertRule)
+	if !ok {
+		return nil, fmt.Errorf("expected a ClusterAlertRule object for the newObj but got %T", newObj)
+	}
+	oldClusteralertrule, ok := oldObj.(*monitoringv1.ClusterAlertRule)
+	if !ok {
+		return nil, fmt.Errorf("expected a ClusterAlertRule object for the oldObj but got %T", oldObj)
+	}
+	clusteralertrulelog.Info("Validation for ClusterAlertRule upon update", "name", clusteralertrule.GetName())
+
+	if !clusteralertrule.DeletionTimestamp.IsZero() || equality.Semantic.DeepEqual(oldClusteralertrule.Spec, clusteralertrule.Spec) {
+		return nil, nil
+	}
+
+	return nil, validateClusterAlertRule(clusteralertrule)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type ClusterAlertRule.
+func (v *ClusterAlertRuleCustomValidator) ValidateDelete(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	// 삭제 시에는 검증할 내용이 없음
+	return nil, nil
+}
+
+// validateClusterAlertRule validates the spec of a ClusterAlertRule and returns an Invalid error
+// aggregating every problem found.
+func validateClusterAlertRule(clusteralertrule *monitoringv1.ClusterAlertRule) error {
+	fldPath := field.NewPath("spec")
+	allErrs := validateAlertRuleSpec(&clusteralertrule.Spec, fldPath)
+	allErrs = append(allErrs, validateClusterScopedSpec(&clusteralertrule.Spec, fldPath)...)
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(
+		schema.GroupKind{Group: monitoringv1.GroupVersion.Group, Kind: "ClusterAlertRule"},
+		clusteralertrule.Name, allErrs)
+}
+
+// validateClusterScopedSpec rejects the fields of an AlertRuleSpec that only apply to namespaced AlertRules
+func validateClusterScopedSpec(spec *monitoringv1.AlertRuleSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	// 클러스터 규칙에서는 적용되지 않으므로 설정 자체를 거부
+	const detail = "not supported for ClusterAlertRules"
+	if spec.WorkloadRef != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("workloadRef"), detail))
+	}
+	if spec.WorkloadSelector != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("workloadSelector"), detail))
+	}
+	if spec.Backend != "" {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("backend"), detail))
+	}
+	if spec.Routing != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("routing"), detail))
+	}
+	if len(spec.Maintenance) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("maintenance"), detail))
+	}
+
+	return allErrs
+}
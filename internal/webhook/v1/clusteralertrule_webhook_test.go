@@ -0,0 +1,82 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	monitoringv1 "github.com/Kim-Yukyung/k8s-alert-rule-operator/api/v1"
+)
+
+var _ = Describe("ClusterAlertRule Webhook", func() {
+	var (
+		obj       *monitoringv1.ClusterAlertRule
+		oldObj    *monitoringv1.ClusterAlertRule
+		validator ClusterAlertRuleCustomValidator
+	)
+
+	BeforeEach(func() {
+		obj = &monitoringv1.ClusterAlertRule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test-clusteralertrule",
+			},
+			Spec: monitoringv1.AlertRuleSpec{
+				Alert: "NodeDown",
+				Expr:  `up{job="node-exporter"} == 0`,
+				For:   "5m",
+			},
+		}
+		oldObj = obj.DeepCopy()
+		validator = ClusterAlertRuleCustomValidator{}
+	})
+
+	Context("When creating or updating ClusterAlertRule under Validating Webhook", func() {
+		It("Should admit creation if the rules are valid", func() {
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+		})
+
+		It("Should validate the rules like an AlertRule", func() {
+			obj.Spec.Expr = "sum(rate(node_cpu_seconds_total[5m]) > 0.9"
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.expr"))
+		})
+
+		It("Should not validate updates leaving the spec unchanged", func() {
+			oldObj.Spec.Expr = "sum(rate(node_cpu_seconds_total[5m]) > 0.9"
+			obj = oldObj.DeepCopy()
+			obj.Finalizers = nil
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).To(BeNil())
+		})
+
+		It("Should deny the fields that do not apply to cluster-wide rules", func() {
+			end := metav1.Now()
+			obj.Spec.WorkloadRef = &monitoringv1.WorkloadReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"}
+			obj.Spec.Backend = monitoringv1.BackendRuler
+			obj.Spec.Routing = &monitoringv1.Routing{Receiver: "ops"}
+			obj.Spec.Maintenance = []monitoringv1.MaintenanceWindow{{End: &end}}
+
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(err).To(HaveOccurred())
+			for _, fld := range []string{"spec.workloadRef", "spec.backend", "spec.routing", "spec.maintenance"} {
+				Expect(err.Error()).To(ContainSubstring(fld))
+			}
+		})
+	})
+})